@@ -1,12 +1,17 @@
 package all
 
 import (
+	_ "github.com/montplusa/auction-game/ai/cfr"
+	_ "github.com/montplusa/auction-game/ai/endgame"
 	_ "github.com/montplusa/auction-game/ai/kimeuti_tarou"
 	_ "github.com/montplusa/auction-game/ai/kimeuti_tarou2"
 	_ "github.com/montplusa/auction-game/ai/kimeuti_tarou3"
+	_ "github.com/montplusa/auction-game/ai/mcts"
 	_ "github.com/montplusa/auction-game/ai/montplusa"
 	_ "github.com/montplusa/auction-game/ai/montplusai"
 	_ "github.com/montplusa/auction-game/ai/montplusai2"
 	_ "github.com/montplusa/auction-game/ai/montplusai3"
+	_ "github.com/montplusa/auction-game/ai/nn"
+	_ "github.com/montplusa/auction-game/ai/oppmodel"
 	_ "github.com/montplusa/auction-game/ai/random"
 )
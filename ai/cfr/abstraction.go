@@ -0,0 +1,111 @@
+package cfr
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/montplusa/auction-game/game"
+)
+
+// The abstraction maps one auction between two players onto a small price-ladder game.
+//
+//   - phase bucket p: early (phases 1-3), middle (4-7), late (8-10)
+//   - jewel value class v: points plus the income over the remaining phases converted to points
+//   - coin bucket m: the mover's total coins relative to the opponent's
+//   - price level k: the current maximum bid measured in steps of 1/levelsPerTable of both
+//     players' coins combined
+//
+// A player to move may pass or raise the price by 1, 2 or 4 levels while they can pay for it.
+const (
+	numPhaseBuckets = 3
+	numValueClasses = 5
+	numCoinBuckets  = 5
+	maxLevel        = 12 // 価格レベルの上限
+	levelsPerTable  = 20 // 両者の合計コインを何レベルに分けるか
+)
+
+// Abstract actions. actPass drops out; the others raise the price by the given number of levels.
+const (
+	actPass = iota
+	actRaise1
+	actRaise2
+	actRaise4
+	numActions
+)
+
+// raiseLevels is the number of levels each action adds to the price.
+var raiseLevels = [numActions]int{0, 1, 2, 4}
+
+// Representative values of the buckets, used by the trainer to compute payoffs.
+var (
+	valueCenters = [numValueClasses]float64{2, 6, 11, 18, 28}
+	coinRatios   = [numCoinBuckets]float64{0.4, 0.65, 1, 1.6, 2.5}
+	coinWeights  = [numCoinBuckets]float64{1.6, 1.25, 1, 0.85, 0.7} // 貧しいほどコインは貴重
+	coinPoints   = [numPhaseBuckets]float64{0.8, 0.6, 0.25}         // コイン 1 枚の得点換算
+	stepCoins    = [numPhaseBuckets]float64{3, 4.5, 5.5}            // 1 レベルあたりの代表的なコイン数
+	phasesLeft   = [numPhaseBuckets]float64{8, 4.5, 1}              // 収入を得られる残りフェーズ数の代表値
+	valueBounds  = [numValueClasses - 1]float64{4, 8, 14, 22}       // 価値クラスの境界
+	coinBounds   = [numCoinBuckets - 1]float64{0.5, 0.8, 1.25, 2}   // コイン比バケットの境界
+	phaseBounds  = [numPhaseBuckets - 1]int{3, 7}                   // フェーズバケットの境界
+)
+
+// phaseBucket returns the phase bucket of phase.
+func phaseBucket(phase int) int {
+	for b, bound := range phaseBounds {
+		if phase <= bound {
+			return b
+		}
+	}
+	return numPhaseBuckets - 1
+}
+
+// valueClass returns the value class of jewel when sold in phase.
+func valueClass(jewel *game.Jewel, phase int) int {
+	income := 0
+	for _, inc := range jewel.Income {
+		income += inc
+	}
+	v := float64(jewel.Point) + float64(income*(game.NumPhases-phase))*coinPoints[phaseBucket(phase)]
+	for c, bound := range valueBounds {
+		if v < bound {
+			return c
+		}
+	}
+	return numValueClasses - 1
+}
+
+// coinBucket returns the bucket of mine/theirs total coins.
+func coinBucket(mine, theirs int) int {
+	r := float64(mine+1) / float64(theirs+1)
+	for b, bound := range coinBounds {
+		if r < bound {
+			return b
+		}
+	}
+	return numCoinBuckets - 1
+}
+
+// affordableLevel returns the highest price level a player in coin bucket m can pay for.
+func affordableLevel(m int) int {
+	r := coinRatios[m]
+	return int(math.Min(maxLevel, math.Floor(levelsPerTable*r/(1+r))))
+}
+
+// legalActions reports which actions a player in coin bucket m may take at price level k.
+func legalActions(m, k int) [numActions]bool {
+	var legal [numActions]bool
+	legal[actPass] = true
+	for a := actRaise1; a < numActions; a++ {
+		legal[a] = k+raiseLevels[a] <= affordableLevel(m)
+	}
+	return legal
+}
+
+// infoKey identifies an information set. lone marks the decision of the last active player
+// when nobody has bid yet, where bidding wins the jewel at the minimum price.
+func infoKey(p, v, m, k int, lone bool) string {
+	if lone {
+		return fmt.Sprintf("%d/%d/%d/lone", p, v, m)
+	}
+	return fmt.Sprintf("%d/%d/%d/%d", p, v, m, k)
+}
@@ -0,0 +1,134 @@
+// Package cfr implements an equilibrium baseline for two-player tables. cmd/cfrtrain solves an
+// abstraction of a single auction with counterfactual regret minimization and writes a strategy
+// file; CFRAI plays that strategy by mapping the real position onto the abstraction.
+package cfr
+
+import (
+	_ "embed"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+
+	"github.com/montplusa/auction-game/ai/toolkit"
+	"github.com/montplusa/auction-game/game"
+)
+
+// defaultStrategy is the output of `go run ./cmd/cfrtrain -out ai/cfr/strategy.json`.
+//
+//go:embed strategy.json
+var defaultStrategy []byte
+
+// StrategyEnv names the environment variable that points the registered AI at another strategy file.
+const StrategyEnv = "AUCTION_CFR_STRATEGY"
+
+// CFRAI plays a precomputed CFR strategy. It is designed for two players; at larger tables it
+// treats the active opponent with the most coins as its only opponent.
+type CFRAI struct {
+	strategy *Strategy
+}
+
+// New returns a CFRAI playing s.
+func New(s *Strategy) *CFRAI {
+	return &CFRAI{strategy: s}
+}
+
+func (ai *CFRAI) GetName() string {
+	return "CFR"
+}
+
+func (ai *CFRAI) SelectAction(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) [3]int {
+	me := as.Turn
+	money := gs.Moneys[me]
+
+	// 最もコインの多い有効な相手を唯一の相手とみなす
+	opp, oppTotal := -1, -1
+	for j, active := range as.Active {
+		if j == me || !active {
+			continue
+		}
+		if t := total(gs.Moneys[j]); t > oppTotal {
+			opp, oppTotal = j, t
+		}
+	}
+	myTotal := total(money)
+
+	p := phaseBucket(gs.Phase)
+	v := valueClass(jewel, gs.Phase)
+	lone := opp < 0 && as.MaxPlayer < 0
+	if opp < 0 && !lone {
+		return [3]int{0, 0, 0}
+	}
+	if lone {
+		probs := ai.strategy.probs(infoKey(p, v, coinBucket(myTotal, myTotal), 0, true), [numActions]bool{true, true, false, false})
+		if sample(probs) == actPass {
+			return [3]int{0, 0, 0}
+		}
+		return toolkit.RaiseTo(as.MaxValue, money, total(as.MaxValue)+1)
+	}
+
+	m := coinBucket(myTotal, oppTotal)
+	step := int(math.Max(1, math.Round(float64(myTotal+oppTotal)/levelsPerTable)))
+	k := 0
+	if as.MaxPlayer >= 0 {
+		k = (total(as.MaxValue) + step - 1) / step
+	}
+	if k > maxLevel {
+		k = maxLevel
+	}
+	a := sample(ai.strategy.probs(infoKey(p, v, m, k, false), legalActions(m, k)))
+	if a == actPass {
+		return [3]int{0, 0, 0}
+	}
+	target := (k + raiseLevels[a]) * step
+	if target <= total(as.MaxValue) {
+		target = total(as.MaxValue) + 1
+	}
+	return toolkit.RaiseTo(as.MaxValue, money, target)
+}
+
+func total(v [3]int) int {
+	return v[0] + v[1] + v[2]
+}
+
+// sample draws an action from probs.
+func sample(probs [numActions]float64) int {
+	r := rand.Float64()
+	for a, p := range probs {
+		if r < p {
+			return a
+		}
+		r -= p
+	}
+	return actPass
+}
+
+// loadStrategy returns the strategy named by StrategyEnv, or the embedded default when it is unset.
+func loadStrategy() (*Strategy, error) {
+	path := os.Getenv(StrategyEnv)
+	if path == "" {
+		return parseStrategy(defaultStrategy)
+	}
+	s, err := LoadStrategy(path)
+	if err != nil {
+		return nil, fmt.Errorf("cfr: %s: %v", StrategyEnv, err)
+	}
+	return s, nil
+}
+
+// init registers the AI even if StrategyEnv names a strategy file that cannot be loaded; the
+// error is then kept in AIInfo.Err, so that runs asking for CFR fail when they resolve it instead
+// of silently playing the default strategy, and runs without it are unaffected.
+func init() {
+	s, err := loadStrategy()
+	game.Register(game.AIInfo{
+		ID:          "cfr",
+		Name:        "CFR",
+		Author:      "MONTplusa",
+		Version:     "1.0",
+		Description: "1 回のオークションを抽象化したゲームの CFR 均衡戦略で入札する 2 人卓向けの基準 AI (戦略ファイルは " + StrategyEnv + " で差し替え可)",
+		Tags:        []string{game.TagBaseline, game.TagExperimental},
+		New:         func() game.AI { return New(s) },
+		Err:         err,
+	})
+}
@@ -0,0 +1,59 @@
+package cfr
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// Strategy is an average strategy produced by Train: the probability of every abstract action
+// (pass, +1, +2, +4 levels) for each information set.
+type Strategy struct {
+	Iterations int                            `json:"iterations"`
+	Probs      map[string][numActions]float64 `json:"strategy"`
+}
+
+// LoadStrategy reads a strategy written by Save.
+func LoadStrategy(path string) (*Strategy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseStrategy(data)
+}
+
+func parseStrategy(data []byte) (*Strategy, error) {
+	s := &Strategy{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes s as JSON to path.
+func (s *Strategy) Save(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// probs returns the action distribution of infoset key restricted to legal actions.
+// Unknown information sets fall back to passing.
+func (s *Strategy) probs(key string, legal [numActions]bool) [numActions]float64 {
+	p, ok := s.Probs[key]
+	sum := 0.0
+	for a := range p {
+		if !legal[a] {
+			p[a] = 0
+		}
+		sum += p[a]
+	}
+	if !ok || sum <= 0 {
+		return [numActions]float64{actPass: 1}
+	}
+	for a := range p {
+		p[a] /= sum
+	}
+	return p
+}
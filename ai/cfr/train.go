@@ -0,0 +1,169 @@
+package cfr
+
+import "math"
+
+// infoSet accumulates regrets and the average strategy of one information set.
+type infoSet struct {
+	legal       [numActions]bool
+	regretSum   [numActions]float64
+	strategySum [numActions]float64
+}
+
+// current returns the regret-matching strategy of is.
+func (is *infoSet) current() [numActions]float64 {
+	var s [numActions]float64
+	sum, n := 0.0, 0.0
+	for a := range s {
+		if !is.legal[a] {
+			continue
+		}
+		n++
+		if is.regretSum[a] > 0 {
+			s[a] = is.regretSum[a]
+			sum += s[a]
+		}
+	}
+	for a := range s {
+		switch {
+		case !is.legal[a]:
+			s[a] = 0
+		case sum > 0:
+			s[a] /= sum
+		default:
+			s[a] = 1 / n
+		}
+	}
+	return s
+}
+
+// trainer runs vanilla CFR on the abstract auction. A uniform chance node picks the phase bucket,
+// the jewel value class and the parent's coin bucket; the other player sees the mirrored bucket.
+type trainer struct {
+	sets map[string]*infoSet
+}
+
+// node is a position of the abstract auction.
+type node struct {
+	p, v   int
+	m      [2]int // 各プレイヤーから見たコインバケット
+	k      int    // 現在の価格レベル
+	holder int    // 最高額を提示しているプレイヤー (-1 なら未入札)
+	toMove int
+	lone   bool // 相手が降りて、未入札のまま 1 人残った状態
+}
+
+// Train runs iterations of CFR and returns the average strategy.
+func Train(iterations int) *Strategy {
+	t := &trainer{sets: map[string]*infoSet{}}
+	for it := 0; it < iterations; it++ {
+		for p := 0; p < numPhaseBuckets; p++ {
+			for v := 0; v < numValueClasses; v++ {
+				for m := 0; m < numCoinBuckets; m++ {
+					root := node{p: p, v: v, m: [2]int{m, numCoinBuckets - 1 - m}, holder: -1}
+					t.cfr(root, 1, 1)
+				}
+			}
+		}
+	}
+	s := &Strategy{Iterations: iterations, Probs: map[string][numActions]float64{}}
+	for key, is := range t.sets {
+		var avg [numActions]float64
+		sum := 0.0
+		for a, w := range is.strategySum {
+			sum += w
+			avg[a] = w
+		}
+		if sum <= 0 {
+			avg = is.current()
+		} else {
+			for a := range avg {
+				avg[a] /= sum
+			}
+		}
+		for a := range avg {
+			avg[a] = math.Round(avg[a]*1e4) / 1e4 // 戦略ファイルを小さく保つ
+		}
+		s.Probs[key] = avg
+	}
+	return s
+}
+
+// payoff returns player 0's utility when winner buys the jewel for coins coins.
+// The game is zero-sum: what the winner gains relative to the table, the loser loses.
+func payoff(n node, winner int, coins float64) float64 {
+	val := valueCenters[n.v] - coins*coinPoints[n.p]*coinWeights[n.m[winner]]
+	if winner == 0 {
+		return val
+	}
+	return -val
+}
+
+// cfr returns the expected utility of player 0 at n, updating regrets along the way.
+// r0 and r1 are the players' reach probabilities.
+func (t *trainer) cfr(n node, r0, r1 float64) float64 {
+	i := n.toMove
+	key := infoKey(n.p, n.v, n.m[i], n.k, n.lone)
+	is, ok := t.sets[key]
+	if !ok {
+		is = &infoSet{}
+		if n.lone {
+			is.legal[actPass], is.legal[actRaise1] = true, true
+		} else {
+			is.legal = legalActions(n.m[i], n.k)
+		}
+		t.sets[key] = is
+	}
+	strat := is.current()
+
+	var util [numActions]float64
+	nodeUtil := 0.0
+	for a := 0; a < numActions; a++ {
+		if !is.legal[a] {
+			continue
+		}
+		if i == 0 {
+			util[a] = t.next(n, a, r0*strat[a], r1)
+		} else {
+			util[a] = t.next(n, a, r0, r1*strat[a])
+		}
+		nodeUtil += strat[a] * util[a]
+	}
+
+	// 手番プレイヤーの視点での後悔を加算する
+	sign, myReach, oppReach := 1.0, r0, r1
+	if i == 1 {
+		sign, myReach, oppReach = -1, r1, r0
+	}
+	for a := 0; a < numActions; a++ {
+		if !is.legal[a] {
+			continue
+		}
+		is.regretSum[a] += oppReach * sign * (util[a] - nodeUtil)
+		is.strategySum[a] += myReach * strat[a]
+	}
+	return nodeUtil
+}
+
+// next applies action a at n and returns player 0's utility of the resulting position.
+func (t *trainer) next(n node, a int, r0, r1 float64) float64 {
+	i, j := n.toMove, 1-n.toMove
+	if n.lone {
+		if a == actPass {
+			return 0 // 誰も落札しない
+		}
+		return payoff(n, i, 1) // 最小額 (コイン 1 枚) で落札
+	}
+	if a == actPass {
+		if n.holder == j {
+			return payoff(n, j, float64(n.k)*stepCoins[n.p])
+		}
+		lone := n
+		lone.toMove, lone.lone = j, true
+		return t.cfr(lone, r0, r1)
+	}
+	raised := n
+	raised.k += raiseLevels[a]
+	raised.holder = i
+	raised.toMove = j
+	return t.cfr(raised, r0, r1)
+}
@@ -0,0 +1,88 @@
+package endgame
+
+import (
+	"github.com/montplusa/auction-game/ai/montplusai3"
+	"github.com/montplusa/auction-game/game"
+)
+
+// Endgame plays like MontplusAI Lv3 until few auctions remain and then bids with Solver.
+// The solver only models open auctions, so sealed-bid and Dutch auctions, coin exchanges and
+// jewel sales are always left to MontplusAI Lv3.
+type Endgame struct {
+	rounds   int                      // 残りオークション数がこれ以下になったらソルバーに切り替える
+	samples  int                      // 未来の宝石列のサンプル数
+	opponent string                   // 相手の方策として仮定する AI の指定文字列
+	fallback *montplusai3.MontplusAI3 // 切り替え前と競り上げ以外で使う AI
+}
+
+// params are the tunable parameters of Endgame.
+var params = []game.ParamSpec{
+	{Name: "rounds", Kind: game.ParamInt, Default: 2, Min: 1, Max: 4, Description: "ソルバーに切り替える残りオークション数"},
+	{Name: "samples", Kind: game.ParamInt, Default: 4, Min: 1, Max: 100, Description: "未来の宝石列のサンプル数"},
+	{Name: "opponent", Kind: game.ParamAI, DefaultAI: "montplusai3", Description: "相手の方策として仮定する AI (登録済み AI の指定文字列)"},
+}
+
+// New returns an Endgame configured with p; missing parameters take their defaults.
+func New(p game.Params) *Endgame {
+	p = p.WithDefaults(params)
+	return &Endgame{
+		rounds:   p.Int("rounds"),
+		samples:  p.Int("samples"),
+		opponent: p.AI("opponent"),
+		fallback: montplusai3.New(game.Params{}),
+	}
+}
+
+func (ai *Endgame) GetName() string {
+	return "Endgame"
+}
+
+func (ai *Endgame) SelectAction(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) [3]int {
+	if gs.RemainingAuctions() > ai.rounds || !gs.Rules.OpenAuction() {
+		return ai.fallback.SelectAction(gs, as, jewel)
+	}
+	opponents := make([]game.AI, len(gs.Scores))
+	for i := range opponents {
+		o, err := game.NewAI(ai.opponent)
+		if err != nil {
+			return ai.fallback.SelectAction(gs, as, jewel)
+		}
+		opponents[i] = o
+	}
+	s := &Solver{Opponents: opponents, Samples: ai.samples}
+	bid, _ := s.Solve(gs, as, jewel, as.Turn)
+	return bid
+}
+
+// SelectSealedBid implements game.SealedBidder like MontplusAI Lv3.
+func (ai *Endgame) SelectSealedBid(gs *game.GameState, jewel *game.Jewel, me int) [3]int {
+	return ai.fallback.SelectSealedBid(gs, jewel, me)
+}
+
+// AcceptAsk implements game.DutchBidder like MontplusAI Lv3.
+func (ai *Endgame) AcceptAsk(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) bool {
+	return ai.fallback.AcceptAsk(gs, as, jewel)
+}
+
+// SelectExchanges implements game.Exchanger like MontplusAI Lv3.
+func (ai *Endgame) SelectExchanges(gs *game.GameState, jewel *game.Jewel, me int) []game.Exchange {
+	return ai.fallback.SelectExchanges(gs, jewel, me)
+}
+
+// SelectSale implements game.Seller like MontplusAI Lv3.
+func (ai *Endgame) SelectSale(gs *game.GameState, me int) int {
+	return ai.fallback.SelectSale(gs, me)
+}
+
+func init() {
+	game.Register(game.AIInfo{
+		ID:            "endgame",
+		Name:          "Endgame",
+		Author:        "MONTplusa",
+		Version:       "1.0",
+		Description:   "残りオークションが少なくなるまでは MontplusAI Lv3 と同じに打ち、以降は相手の方策を仮定して残りの入札上限の組み合わせを全探索する (opponent: 相手の方策として仮定する AI、既定は MontplusAI Lv3)",
+		Tags:          []string{game.TagExperimental},
+		Params:        params,
+		NewWithParams: func(p game.Params) game.AI { return New(p) },
+	})
+}
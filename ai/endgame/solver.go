@@ -0,0 +1,181 @@
+// Package endgame solves the last auctions of a game by exhaustive search and provides an AI that
+// switches to the solver when few auctions remain.
+//
+// In the final phase income no longer pays out, so what is left is how to spend known coins on
+// the remaining jewels. The solver fixes a policy for every opponent and, for each of a few
+// sampled jewel sequences, tries every combination of bidding limits over the remaining auctions.
+package endgame
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/montplusa/auction-game/ai/toolkit"
+	"github.com/montplusa/auction-game/game"
+	"github.com/montplusa/auction-game/generator"
+)
+
+// DefaultLevels is the set of raises, over the current maximum total, tried as bidding limits.
+var DefaultLevels = []int{1, 2, 3, 5, 8, 12, 17, 25, 35, 50}
+
+// Solver searches the remaining auctions exhaustively.
+//
+// In every auction the solver's player follows one of a few strategies: pass, raise the total by
+// one coin at a time up to a limit, or jump to a minimal dominant bid. The strategy of each
+// remaining auction is chosen after its jewel is revealed, so the search is exact for the last
+// auction and, for earlier ones, optimal on each sampled jewel sequence (the sequences are then
+// averaged for the current decision).
+type Solver struct {
+	Opponents []game.AI // 各席の方策の仮定 (自分の席は無視される)
+	Samples   int       // 未来の宝石列のサンプル数 (現在のオークションが最後なら使わない)
+	Levels    []int     // 上限額の候補 (空なら DefaultLevels)
+}
+
+// strategy is how the solver's player bids in one auction.
+type strategy struct {
+	limit int  // 合計額の上限 (-1 なら降りる)
+	jump  bool // true なら最初の手番で最も安い最小支配入札を出し、以後は降りる
+}
+
+// policy returns the bidding function of st for player me.
+func (st strategy) policy(me int) game.Policy {
+	jumped := false
+	return func(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) [3]int {
+		if st.jump {
+			if jumped {
+				return toolkit.Pass
+			}
+			jumped = true
+			return cheapestDominant(gs, as, me)
+		}
+		target := as.MaxValue[0] + as.MaxValue[1] + as.MaxValue[2] + 1
+		if target > st.limit {
+			return toolkit.Pass
+		}
+		return toolkit.RaiseTo(as.MaxValue, gs.Moneys[me], target)
+	}
+}
+
+// strategies enumerates the strategies of me in auction as.
+func (s *Solver) strategies(gs *game.GameState, as *game.AuctionState, me int) []strategy {
+	levels := s.Levels
+	if len(levels) == 0 {
+		levels = DefaultLevels
+	}
+	money := gs.Moneys[me]
+	total := as.MaxValue[0] + as.MaxValue[1] + as.MaxValue[2]
+	budget := money[0] + money[1] + money[2]
+
+	res := []strategy{{limit: -1}}
+	last := total
+	for _, l := range levels {
+		limit := total + l
+		if limit > budget {
+			limit = budget
+		}
+		if limit > last {
+			res = append(res, strategy{limit: limit})
+			last = limit
+		}
+	}
+	return append(res, strategy{jump: true})
+}
+
+// cheapestDominant returns the legal minimal dominant bid of me with the smallest total, or a
+// pass if there is none.
+func cheapestDominant(gs *game.GameState, as *game.AuctionState, me int) [3]int {
+	best, bestTotal := toolkit.Pass, math.MaxInt
+	for _, b := range toolkit.MinimalDominantRaises(gs, as) {
+		if t := b[0] + b[1] + b[2]; t < bestTotal && game.IsLegalBid(gs, as, me, b) {
+			best, bestTotal = b, t
+		}
+	}
+	return best
+}
+
+// Solve returns the best bid of player me, who is to move in as, and its expected value.
+// Values are (N - rank)/(N - 1) at the end of the game plus a small bonus for the score margin
+// over the best opponent. Future jewels are sampled from the jewel source of gs.Rules; Solve passes
+// if the source is unknown or gs.Rules is not an open auction, the only kind the solver models.
+func (s *Solver) Solve(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel, me int) ([3]int, float64) {
+	if !gs.Rules.OpenAuction() {
+		return toolkit.Pass, 0
+	}
+	samples := s.Samples
+	if samples < 1 || gs.RemainingAuctions() == 1 {
+		samples = 1
+	}
+	src, err := generator.ForRules(gs.Rules)
+	if err != nil {
+		return toolkit.Pass, 0
+	}
+	futures := make([][]*game.Jewel, samples)
+	for i := range futures {
+		futures[i] = make([]*game.Jewel, gs.RemainingAuctions())
+		for j := range futures[i] {
+			futures[i][j] = src.Generate(rand.Intn)
+		}
+	}
+
+	// 次の手番で選び直せるので、最初の入札が同じ戦略のうち最良のものをその入札の値とする
+	firsts := make(map[[3]int]float64)
+	for _, st := range s.strategies(gs, as, me) {
+		first := st.policy(me)(gs, as, jewel)
+		sum := 0.0
+		for _, future := range futures {
+			// play が NextJewel を future から引くものに差し替える
+			sim := game.NewSimulator(gs, as, jewel, s.Opponents, generator.Sampler(src))
+			sum += s.play(sim, future, st, me)
+		}
+		if v, ok := firsts[first]; !ok || sum/float64(samples) > v {
+			firsts[first] = sum / float64(samples)
+		}
+	}
+
+	best, bestVal := toolkit.Pass, math.Inf(-1)
+	for bid, v := range firsts {
+		if v > bestVal || v == bestVal && bid == toolkit.Pass {
+			best, bestVal = bid, v
+		}
+	}
+	return best, bestVal
+}
+
+// play finishes the current auction of sim with me following st and returns the value of the
+// best continuation. The jewel of each later auction is taken from future, indexed by the
+// number of auctions remaining, so that sibling branches see the same jewels.
+func (s *Solver) play(sim *game.Simulator, future []*game.Jewel, st strategy, me int) float64 {
+	sim.NextJewel = func() *game.Jewel { return future[sim.State.RemainingAuctions()-1] }
+	policies := append([]game.AI(nil), s.Opponents...)
+	policies[me] = st.policy(me)
+	sim.Policies = policies
+	sim.FinishAuction()
+	return s.search(sim, future, me)
+}
+
+// search returns the value for me of the best strategy from the start of the auction in sim.
+func (s *Solver) search(sim *game.Simulator, future []*game.Jewel, me int) float64 {
+	if sim.Done() {
+		return value(sim.State, me)
+	}
+	best := math.Inf(-1)
+	for _, st := range s.strategies(sim.State, sim.Auction, me) {
+		if v := s.play(sim.Clone(), future, st, me); v > best {
+			best = v
+		}
+	}
+	return best
+}
+
+// value scores the final state for me, with scores including the set-collection bonus as in Ranks.
+func value(gs *game.GameState, me int) float64 {
+	N := len(gs.Scores)
+	scores := game.TotalScores(gs)
+	best := math.MinInt
+	for j, sc := range scores {
+		if j != me && sc > best {
+			best = sc
+		}
+	}
+	return float64(N-game.Ranks(gs)[me])/float64(N-1) + 0.001*float64(scores[me]-best)
+}
@@ -64,7 +64,12 @@ func (ai *KimeutiAI) SelectAction(gs *game.GameState,
 }
 
 func init() {
-	game.RegisterAI("決打太郎", func() game.AI {
-		return &KimeutiAI{}
+	game.Register(game.AIInfo{
+		ID:          "kimeuti_tarou",
+		Name:        "決打太郎",
+		Version:     "1.0",
+		Description: "所持コイン総額の指数分布に従う割合まで、ランダムな色を 1 枚ずつ上乗せして入札する",
+		Tags:        []string{game.TagBaseline},
+		New:         func() game.AI { return &KimeutiAI{} },
 	})
 }
@@ -68,7 +68,12 @@ func (ai *KimeutiAI) SelectAction(gs *game.GameState,
 }
 
 func init() {
-	game.RegisterAI("決打太郎Lv2", func() game.AI {
-		return &KimeutiAI{}
+	game.Register(game.AIInfo{
+		ID:          "kimeuti_tarou2",
+		Name:        "決打太郎Lv2",
+		Version:     "1.0",
+		Description: "宝石の得点と収入、フェーズから決めた額まで、ランダムな色を 1 枚ずつ上乗せして入札する",
+		Tags:        []string{game.TagBaseline},
+		New:         func() game.AI { return &KimeutiAI{} },
 	})
 }
@@ -98,7 +98,12 @@ func (ai *KimeutiAI) SelectAction(gs *game.GameState,
 }
 
 func init() {
-	game.RegisterAI("決打太郎Lv3", func() game.AI {
-		return &KimeutiAI{}
+	game.Register(game.AIInfo{
+		ID:          "kimeuti_tarou3",
+		Name:        "決打太郎Lv3",
+		Version:     "1.0",
+		Description: "Lv2 の入札額で、上乗せする色を相手の残りコインとの比率で選ぶ",
+		Tags:        []string{game.TagBaseline},
+		New:         func() game.AI { return &KimeutiAI{} },
 	})
 }
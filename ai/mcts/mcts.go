@@ -0,0 +1,245 @@
+package mcts
+
+import (
+	"math"
+	"time"
+
+	"github.com/montplusa/auction-game/ai/toolkit"
+	"github.com/montplusa/auction-game/game"
+	"github.com/montplusa/auction-game/generator"
+
+	// 既定のロールアウト方策
+	_ "github.com/montplusa/auction-game/ai/kimeuti_tarou3"
+)
+
+// MCTS searches the current auction with information-set Monte Carlo tree search.
+// Every iteration determinizes the unknown future by sampling fresh jewels from the game's jewel
+// source (Rules.Jewels), walks the tree of the current auction choosing actions with UCT for
+// whichever player is to move, and after the auction is settled plays the game on with the rollout
+// policy for every seat. Rewards mix the final rank with the score relative to the leader.
+type MCTS struct {
+	iterations  int           // 反復回数の上限
+	timeLimit   time.Duration // 1 手あたりの時間上限 (0 なら無制限)
+	exploration float64       // UCT の探索係数
+	horizon     int           // ロールアウトで進めるフェーズ数 (0 ならゲーム終了まで)
+	rollout     game.AIInfo   // ロールアウト方策の AI
+	rolloutP    game.Params   // ロールアウト方策のパラメータ
+
+	sourceName string                // source を引いた Rules.Jewels
+	source     generator.JewelSource // 未来の宝石の生成規則 (最初の手番で Rules.Jewels から引く)
+}
+
+// params are the tunable parameters of MCTS.
+var params = []game.ParamSpec{
+	{Name: "iterations", Kind: game.ParamInt, Default: 200, Min: 1, Max: 100000, Description: "1 手あたりの反復回数"},
+	{Name: "time_ms", Kind: game.ParamInt, Default: 0, Min: 0, Max: 60000, Description: "1 手あたりの時間上限 (ミリ秒、0 で無制限)"},
+	{Name: "c", Kind: game.ParamFloat, Default: 0.7, Min: 0, Max: 10, Description: "UCT の探索係数"},
+	{Name: "horizon", Kind: game.ParamInt, Default: 0, Min: 0, Max: game.NumPhases, Description: "ロールアウトで進めるフェーズ数 (0 でゲーム終了まで)"},
+	{Name: "rollout", Kind: game.ParamAI, DefaultAI: "kimeuti_tarou3", Description: "ロールアウト方策の AI (登録済み AI の指定文字列)"},
+}
+
+// New returns an MCTS configured with p; missing parameters take their defaults.
+// It panics if the rollout spec does not resolve, which ValidateParams rules out.
+func New(p game.Params) *MCTS {
+	p = p.WithDefaults(params)
+	rollout, rolloutP, err := game.ParseAISpec(p.AI("rollout"))
+	if err != nil {
+		panic("mcts: " + err.Error())
+	}
+	return &MCTS{
+		iterations:  p.Int("iterations"),
+		timeLimit:   time.Duration(p.Int("time_ms")) * time.Millisecond,
+		exploration: p.Float("c"),
+		horizon:     p.Int("horizon"),
+		rollout:     rollout,
+		rolloutP:    rolloutP,
+	}
+}
+
+func (ai *MCTS) GetName() string {
+	return "MCTS"
+}
+
+// node is a decision point of the current auction; its state is determined by the path from the root.
+type node struct {
+	player   int
+	actions  [][3]int
+	children []*node
+	visits   []int
+	rewards  []float64 // 行動ごとの player の報酬合計
+	total    int
+}
+
+func newNode(sim *game.Simulator) *node {
+	gs, as := sim.State, sim.Auction
+	me := as.Turn
+	actions := [][3]int{toolkit.Pass}
+	actions = append(actions, game.MinimalRaises(gs, as, me)...)
+	// 1 色に 2, 4, 8 枚上乗せする大きめの入札
+	for _, k := range []int{2, 4, 8} {
+		for c := 0; c < 3; c++ {
+			b := as.MaxValue
+			b[c] += k
+			if game.IsLegalBid(gs, as, me, b) {
+				actions = append(actions, b)
+			}
+		}
+	}
+	for _, b := range toolkit.MinimalDominantRaises(gs, as) {
+		if game.IsLegalBid(gs, as, me, b) {
+			actions = append(actions, b)
+		}
+	}
+	actions = toolkit.Dedupe(actions)
+	return &node{
+		player:   me,
+		actions:  actions,
+		children: make([]*node, len(actions)),
+		visits:   make([]int, len(actions)),
+		rewards:  make([]float64, len(actions)),
+	}
+}
+
+// selectAction returns the index of the action maximizing UCT, trying unvisited actions first.
+func (n *node) selectAction(c float64) int {
+	best, bestVal := 0, math.Inf(-1)
+	for i := range n.actions {
+		if n.visits[i] == 0 {
+			return i
+		}
+		v := n.rewards[i]/float64(n.visits[i]) + c*math.Sqrt(math.Log(float64(n.total))/float64(n.visits[i]))
+		if v > bestVal {
+			best, bestVal = i, v
+		}
+	}
+	return best
+}
+
+func (ai *MCTS) SelectAction(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) [3]int {
+	N := len(gs.Scores)
+	policies := make([]game.AI, N)
+	for i := range policies {
+		policies[i] = ai.rollout.Instantiate(ai.rolloutP)
+	}
+	base := game.NewSimulator(gs, as, jewel, policies, generator.Sampler(ai.jewelSource(gs.Rules)))
+	root := newNode(base)
+	if len(root.actions) == 1 {
+		return toolkit.Pass
+	}
+
+	deadline := time.Now().Add(ai.timeLimit)
+	for it := 0; it < ai.iterations; it++ {
+		if ai.timeLimit > 0 && time.Now().After(deadline) {
+			break
+		}
+		ai.iterate(root, base.Clone())
+	}
+
+	best := 0
+	for i := range root.actions {
+		if root.visits[i] > root.visits[best] {
+			best = i
+		}
+	}
+	return root.actions[best]
+}
+
+// AcceptAsk implements game.DutchBidder by asking the rollout policy: the search only models
+// open and sealed-bid auctions, so MCTS does not search Dutch auctions.
+func (ai *MCTS) AcceptAsk(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) bool {
+	return gs.AcceptsAsk(ai.rollout.Instantiate(ai.rolloutP), as, jewel)
+}
+
+// jewelSource returns the jewel source of rules, from which the future jewels are sampled. It
+// panics if the source is unknown, which the runners rule out before the game starts.
+func (ai *MCTS) jewelSource(rules game.Rules) generator.JewelSource {
+	if ai.source == nil || ai.sourceName != rules.Jewels {
+		src, err := generator.ForRules(rules)
+		if err != nil {
+			panic("mcts: " + err.Error())
+		}
+		ai.source, ai.sourceName = src, rules.Jewels
+	}
+	return ai.source
+}
+
+// iterate runs one selection / expansion / rollout / backpropagation pass on sim.
+func (ai *MCTS) iterate(root *node, sim *game.Simulator) {
+	type step struct {
+		n *node
+		a int
+	}
+	var path []step
+	n := root
+	for {
+		a := n.selectAction(ai.exploration)
+		path = append(path, step{n, a})
+		if sim.Apply(n.actions[a]) || skipInactive(sim) {
+			break // オークション決着
+		}
+		if n.children[a] == nil {
+			// 展開したら残りはロールアウト方策で決着させる
+			n.children[a] = newNode(sim)
+			sim.FinishAuction()
+			break
+		}
+		n = n.children[a]
+	}
+	rewards := ai.playout(sim)
+	for _, s := range path {
+		s.n.visits[s.a]++
+		s.n.total++
+		s.n.rewards[s.a] += rewards[s.n.player]
+	}
+}
+
+// skipInactive passes the turn over players who already dropped out and reports whether the
+// auction was settled meanwhile.
+func skipInactive(sim *game.Simulator) bool {
+	for !sim.Auction.Active[sim.Turn()] {
+		if sim.Step() {
+			return true
+		}
+	}
+	return false
+}
+
+// playout continues the game with the rollout policies for ai.horizon phases (or to the end) and
+// returns a reward in [0,1] for every player.
+func (ai *MCTS) playout(sim *game.Simulator) []float64 {
+	limit := sim.State.Phase + ai.horizon
+	for !sim.Done() && (ai.horizon == 0 || sim.State.Phase < limit) {
+		sim.Step()
+	}
+	N := len(sim.State.Scores)
+	rewards := make([]float64, N)
+	scores := game.TotalScores(sim.State)
+	best := 1
+	for _, sc := range scores {
+		if sc > best {
+			best = sc
+		}
+	}
+	// 順位と、首位に対する得点比を半々で混ぜる (呪いの宝石で得点が負なら比は 0)
+	for i, r := range game.Ranks(sim.State) {
+		ratio := 0.0
+		if scores[i] > 0 {
+			ratio = float64(scores[i]) / float64(best)
+		}
+		rewards[i] = 0.5*float64(N-r)/float64(N-1) + 0.5*ratio
+	}
+	return rewards
+}
+
+func init() {
+	game.Register(game.AIInfo{
+		ID:            "mcts",
+		Name:          "MCTS",
+		Author:        "MONTplusa",
+		Version:       "1.0",
+		Description:   "現在のオークションを ISMCTS で探索し、決着後は未来の宝石をサンプリングして既存 AI のロールアウトで先を読む (rollout: 方策の AI、既定は決打太郎Lv3)",
+		Tags:          []string{game.TagExperimental},
+		Params:        params,
+		NewWithParams: func(p game.Params) game.AI { return New(p) },
+	})
+}
@@ -5,13 +5,32 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/montplusa/auction-game/ai/toolkit"
 	"github.com/montplusa/auction-game/game"
 )
 
 const DEPTH = 11
 
 // Montplusa implements a bidding AI using minimal dominant bids, +1-step, WTP, random bids, and deterministic evaluation.
-type Montplusa struct{}
+type Montplusa struct {
+	depth      int // 評価で見るフェーズ数 (1～DEPTH)
+	candidates int // ランダム入札候補の数
+}
+
+// params are the tunable parameters of Montplusa.
+var params = []game.ParamSpec{
+	{Name: "depth", Kind: game.ParamInt, Default: DEPTH, Min: 1, Max: DEPTH, Description: "評価で見るフェーズ数"},
+	{Name: "candidates", Kind: game.ParamInt, Default: 100, Min: 0, Max: 1000, Description: "ランダム入札候補の数"},
+}
+
+// New returns a Montplusa configured with p; missing parameters take their defaults.
+func New(p game.Params) *Montplusa {
+	p = p.WithDefaults(params)
+	return &Montplusa{
+		depth:      p.Int("depth"),
+		candidates: p.Int("candidates"),
+	}
+}
 
 func (ai *Montplusa) GetName() string {
 	return "Montplusa"
@@ -29,307 +48,76 @@ func (ai *Montplusa) SelectAction(gs *game.GameState, as *game.AuctionState, jew
 	candidates = append(candidates, [3]int{0, 0, 0})
 
 	// +1-step
-
-	for c := 0; c < 3; c++ {
-		plusOne := [3]int{maxVal[0], maxVal[1], maxVal[2]}
-		plusOne[c] = maxVal[c] + 1
-		if plusOne[c] <= budgets[c] {
-			candidates = append(candidates, plusOne)
-		}
-
-	}
+	candidates = append(candidates, game.MinimalRaises(gs, as, me)...)
 
 	// minimal dominant bids
-	doms := generateMinimalDominant(gs, as)
-	for _, b := range doms {
-		valid := true
-		for c := 0; c < 3; c++ {
-			if b[c] > budgets[c] {
-				valid = false
-				break
-			}
-		}
-		if valid {
+	for _, b := range toolkit.MinimalDominantBidsWith(gs, as, toolkit.DominantOptions{Clamp: toolkit.ClampLeading}) {
+		if toolkit.Affordable(b, budgets) {
 			candidates = append(candidates, b)
 		}
 	}
 
-	// random bids (up to 100 candidates in [maxVal..budgets])
+	// random bids (up to ai.candidates candidates in [maxVal..budgets], skewed toward maxVal)
 	rand.Seed(time.Now().UnixNano())
-	randBids := make([][3]int, 0)
-	tries := 0
-	for len(randBids) < 100 && tries < 1000 {
-		tries++
-		var rb [3]int
-		ok := true
-		for c := 0; c < 3; c++ {
-			lb, ub := maxVal[c], budgets[c]
-			if ub < lb {
-				ok = false
-				break
-			}
-			r := rand.Float64()
-			if r < 0.1 {
-				rb[c] = lb
-			} else {
-				rb[c] = lb + int(float64((ub-lb))-math.Sqrt(rand.Float64()*float64((ub-lb+1)*(ub-lb+1))))
-			}
-		}
-		if ok {
-			randBids = append(randBids, rb)
-		}
-	}
-	for _, rb := range randBids {
-		candidates = append(candidates, rb)
-	}
-
-	// dedupe
-	seen := make(map[[3]int]bool)
-	finalCands := make([][3]int, 0)
-	for _, b := range candidates {
-		if !seen[b] {
-			seen[b] = true
-			finalCands = append(finalCands, b)
+	candidates = append(candidates, toolkit.RandomBids(maxVal, budgets, ai.candidates, func(lb, ub int) int {
+		if rand.Float64() < 0.1 {
+			return lb
 		}
-	}
+		return lb + int(float64((ub-lb))-math.Sqrt(rand.Float64()*float64((ub-lb+1)*(ub-lb+1))))
+	})...)
 
 	// 3. Evaluate candidates deterministically: pass->loss, bid->win
+	ev := toolkit.EvaluatorFunc(func(gs *game.GameState, me int) float64 {
+		return evaluateState(gs, me, ai.depth)
+	})
 	bestVal := math.Inf(-1)
-	bestBid := [3]int{0, 0, 0}
-	// fmt.Println(gs.Phase, gs.Round)
-	for _, bid := range finalCands {
-		// skip non-pass bids that don't exceed current max
-		if bid != [3]int{0, 0, 0} {
-			exceed := false
-			greater := true
-			for c := 0; c < 3; c++ {
-				if bid[c] > maxVal[c] {
-					exceed = true
-				}
-				if bid[c] < maxVal[c] {
-					greater = false
-					break
-				}
-			}
-			if !exceed || !greater {
-				continue
-			}
-		}
-
+	bestBid := toolkit.Pass
+	for _, bid := range toolkit.Dedupe(candidates) {
 		var val float64
-		if bid == [3]int{0, 0, 0} {
-			// pass -> simulate loss
-			var lossState *game.GameState
-			lossState = gs.Copy()
-			minv := evaluateState(lossState, me)
-			for i := range gs.Scores {
-				if i == me {
-					continue
-				}
-				if !as.Active[i] {
-					continue
-				}
-				greater := false
-				ok := true
-				for c := 0; c < 3; c++ {
-					if as.MaxValue[c] < gs.Moneys[i][c] {
-						greater = true
-					}
-					if as.MaxValue[c] > gs.Moneys[i][c] {
-						ok = false
-						break
-					}
-				}
-				if !ok {
-					continue
-				}
-				if !greater && i != as.MaxPlayer {
-					continue
-				}
-				lossState = simulate(gs, jewel, i, as.MaxValue)
-				v := evaluateState(lossState, me)
-				if v < minv {
-					minv = v
-				}
-			}
-			val = minv
+		if bid == toolkit.Pass {
+			// pass -> worst case over opponents taking it at the current max
+			val = toolkit.PassValue(gs, as, jewel, me, ev)
+		} else if toolkit.Raises(bid, maxVal) {
+			// bid -> worst case of winning or being outbid
+			val = toolkit.BidValue(gs, as, jewel, me, bid, ev)
 		} else {
-			// bid -> simulate win
-			newState := simulate(gs, jewel, me, bid)
-			minv := evaluateState(newState, me)
-			for i := range gs.Scores {
-				if i == me {
-					continue
-				}
-				if !as.Active[i] {
-					continue
-				}
-				greater := false
-				ok := true
-				for c := 0; c < 3; c++ {
-					if bid[c] < gs.Moneys[i][c] {
-						greater = true
-					}
-					if bid[c] > gs.Moneys[i][c] {
-						ok = false
-						break
-					}
-				}
-				if !ok {
-					continue
-				}
-				if !greater {
-					continue
-				}
-				newState = simulate(gs, jewel, i, bid)
-				v := evaluateState(newState, me)
-				if v < minv {
-					minv = v
-				}
-			}
-			val = minv
+			continue
 		}
 
 		if val > bestVal {
 			bestVal = val
 			bestBid = bid
 		}
-		// fmt.Println(bid, val)
 	}
 
 	return bestBid
 }
 
 func init() {
-	game.RegisterAI("Montplusa", func() game.AI { return &Montplusa{} })
-}
-
-// generateMinimalDominant enumerates minimal dominant bids
-func generateMinimalDominant(gs *game.GameState, as *game.AuctionState) [][3]int {
-	me := as.Turn
-	maxVal := as.MaxValue
-
-	// thresholds per color
-	T := make([][]int, 3)
-	for c := 0; c < 3; c++ {
-		set := map[int]bool{maxVal[c] + 1: true, maxVal[c]: true}
-		for j, active := range as.Active {
-			if !active || j == me {
-				continue
-			}
-			set[gs.Moneys[j][c]+1] = true
-		}
-		for v := range set {
-			T[c] = append(T[c], v)
-		}
-		sortInts(T[c])
-	}
-
-	// enumerate minimal
-	cands := make([][3]int, 0)
-	for _, r := range T[0] {
-		for _, g := range T[1] {
-			for _, b := range T[2] {
-				bid := [3]int{r, g, b}
-				same := true
-				for i := 0; i < 3; i++ {
-					if bid[i] < maxVal[i] {
-						bid[i] = maxVal[i]
-					}
-					if bid[i] != maxVal[i] {
-						same = false
-						break
-					}
-				}
-				if same {
-					continue
-				}
-				if isDominantBid(bid, gs, as) {
-					cands = append(cands, bid)
-				}
-			}
-		}
-	}
-	return filterMinimal(cands)
-}
-
-// isDominantBid checks if bid exceeds each active opponent in at least one color
-func isDominantBid(bid [3]int, gs *game.GameState, as *game.AuctionState) bool {
-	me := as.Turn
-	for j, active := range as.Active {
-		if !active || j == me {
-			continue
-		}
-		exceeds := false
-		for c := 0; c < 3; c++ {
-			if bid[c] > gs.Moneys[j][c] {
-				exceeds = true
-				break
-			}
-		}
-		if !exceeds {
-			return false
-		}
-	}
-	return true
-}
-
-// filterMinimal removes bids dominated by another candidate
-func filterMinimal(cands [][3]int) [][3]int {
-	res := make([][3]int, 0)
-	for i, a := range cands {
-		keep := true
-		for j, b := range cands {
-			if i == j {
-				continue
-			}
-			allLeq, anyLess := true, false
-			for c := 0; c < 3; c++ {
-				if b[c] > a[c] {
-					allLeq = false
-					break
-				}
-				if b[c] < a[c] {
-					anyLess = true
-				}
-			}
-			if allLeq && anyLess {
-				keep = false
-				break
-			}
-		}
-		if keep {
-			res = append(res, a)
-		}
-	}
-	return res
-}
-
-// simulate applies state changes
-func simulate(gs *game.GameState, jewel *game.Jewel, winner int, bid [3]int) *game.GameState {
-	next := gs.Copy()
-	for c := 0; c < 3; c++ {
-		next.Moneys[winner][c] -= bid[c]
-	}
-	next.Scores[winner] += jewel.Point
-	for c := 0; c < 3; c++ {
-		next.Incomes[winner][c] += jewel.Income[c]
-	}
-	return next
+	game.Register(game.AIInfo{
+		ID:            "montplusa",
+		Name:          "Montplusa",
+		Author:        "MONTplusa",
+		Version:       "1.0",
+		Description:   "最小支配入札・+1 入札・ランダム入札を候補に、最大 11 フェーズ先までの資金比を相手ごとに評価して選ぶ",
+		Tags:          []string{game.TagExperimental},
+		Params:        params,
+		NewWithParams: func(p game.Params) game.AI { return New(p) },
+	})
 }
 
-// evaluateState scores a GameState for player me with dynamic weighted sums
-func evaluateState(gs *game.GameState, me int) float64 {
+// evaluateState scores a GameState for player me with dynamic weighted sums over the next depth phases
+func evaluateState(gs *game.GameState, me int, depth int) float64 {
 	numPlayers := len(gs.Scores)
 	value := 0.0
 	for p := 0; p < numPlayers; p++ {
-		v := evaluateVersus(gs, me, p)
+		v := evaluateVersus(gs, me, p, depth)
 		value += v
 	}
 	return value
 }
 
-func evaluateVersus(gs *game.GameState, me int, opp int) float64 {
+func evaluateVersus(gs *game.GameState, me int, opp int, depth int) float64 {
 	value := 0.0
 	numPlayers := len(gs.Scores)
 	weights := [DEPTH]float64{1.0, 0.7, 0.4, 0.2, 0.2, 0.2, 0.2, 0.2, 0.2, 0.2, 0.0}
@@ -341,7 +129,7 @@ func evaluateVersus(gs *game.GameState, me int, opp int) float64 {
 		weights2[i] = weights[i-1]*roundProp + weights[i]*(1-roundProp)
 	}
 
-	for i := 0; i < DEPTH; i++ {
+	for i := 0; i < depth; i++ {
 		if gs.Phase+i > 10 {
 			break
 		}
@@ -371,16 +159,3 @@ func evaluateVersus(gs *game.GameState, me int, opp int) float64 {
 
 	return value
 }
-
-// sortInts sorts small integer slices using insertion sort
-func sortInts(a []int) {
-	for i := 1; i < len(a); i++ {
-		key := a[i]
-		j := i - 1
-		for j >= 0 && a[j] > key {
-			a[j+1] = a[j]
-			j--
-		}
-		a[j+1] = key
-	}
-}
@@ -5,11 +5,27 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/montplusa/auction-game/ai/toolkit"
 	"github.com/montplusa/auction-game/game"
 )
 
 // MontplusAI implements a bidding AI using minimal dominant bids, +1-step, WTP, random bids, and deterministic evaluation.
-type MontplusAI struct{}
+type MontplusAI struct {
+	alpha float64 // WTP: 得点 1 あたりの価値
+	beta  float64 // WTP: 残りフェーズ 1 回分の収入 1 あたりの価値
+}
+
+// params are the tunable parameters of MontplusAI.
+var params = []game.ParamSpec{
+	{Name: "alpha", Kind: game.ParamFloat, Default: 1.2, Min: 0, Max: 10, Description: "WTP の得点係数"},
+	{Name: "beta", Kind: game.ParamFloat, Default: 0.8, Min: 0, Max: 10, Description: "WTP の収入係数"},
+}
+
+// New returns a MontplusAI configured with p; missing parameters take their defaults.
+func New(p game.Params) *MontplusAI {
+	p = p.WithDefaults(params)
+	return &MontplusAI{alpha: p.Float("alpha"), beta: p.Float("beta")}
+}
 
 func (ai *MontplusAI) GetName() string {
 	return "MontplusAI Lv1"
@@ -23,12 +39,11 @@ func (ai *MontplusAI) SelectAction(gs *game.GameState, as *game.AuctionState, je
 	phaseLeft := 10 - gs.Phase
 
 	// 1. Calculate Willingness to Pay (WTP)
-	alpha, beta := 1.2, 0.8
-	scoreVal := alpha * float64(jewel.Point)
+	scoreVal := ai.alpha * float64(jewel.Point)
 	incomeVal := 0.0
 	for _, inc := range jewel.Income {
 		if inc > 0 {
-			incomeVal = beta * float64(inc) * float64(phaseLeft)
+			incomeVal = ai.beta * float64(inc) * float64(phaseLeft)
 		}
 	}
 	wtp := int(math.Round(scoreVal + incomeVal))
@@ -60,60 +75,22 @@ func (ai *MontplusAI) SelectAction(gs *game.GameState, as *game.AuctionState, je
 	}
 
 	// minimal dominant bids
-	doms := generateMinimalDominant(gs, as)
-	for _, b := range doms {
-		valid := true
-		for c := 0; c < 3; c++ {
-			if b[c] > budgets[c] {
-				valid = false
-				break
-			}
-		}
-		if valid {
+	for _, b := range toolkit.MinimalDominantBidsWith(gs, as, toolkit.DominantOptions{WithoutMax: true}) {
+		if toolkit.Affordable(b, budgets) {
 			candidates = append(candidates, b)
 		}
 	}
 
 	// random bids (up to 100 candidates in [maxVal..budgets])
 	rand.Seed(time.Now().UnixNano())
-	randBids := make([][3]int, 0)
-	tries := 0
-	for len(randBids) < 100 && tries < 1000 {
-		tries++
-		var rb [3]int
-		ok := true
-		for c := 0; c < 3; c++ {
-			lb, ub := maxVal[c], budgets[c]
-			if ub < lb {
-				ok = false
-				break
-			}
-			rb[c] = lb + rand.Intn(ub-lb+1)
-		}
-		if ok {
-			randBids = append(randBids, rb)
-		}
-	}
-	for _, rb := range randBids {
-		candidates = append(candidates, rb)
-	}
-
-	// dedupe
-	seen := make(map[[3]int]bool)
-	finalCands := make([][3]int, 0)
-	for _, b := range candidates {
-		if !seen[b] {
-			seen[b] = true
-			finalCands = append(finalCands, b)
-		}
-	}
+	candidates = append(candidates, toolkit.RandomBids(maxVal, budgets, 100, toolkit.Uniform)...)
 
 	// 3. Evaluate candidates deterministically: pass->loss, bid->win
 	bestVal := math.Inf(-1)
-	bestBid := [3]int{0, 0, 0}
-	for _, bid := range finalCands {
+	bestBid := toolkit.Pass
+	for _, bid := range toolkit.Dedupe(candidates) {
 		// skip non-pass bids that don't exceed current max
-		if bid != [3]int{0, 0, 0} {
+		if bid != toolkit.Pass {
 			exceed := false
 			for c := 0; c < 3; c++ {
 				if bid[c] > maxVal[c] {
@@ -127,19 +104,16 @@ func (ai *MontplusAI) SelectAction(gs *game.GameState, as *game.AuctionState, je
 		}
 
 		var val float64
-		if bid == [3]int{0, 0, 0} {
-			// pass -> simulate loss
-			var lossState *game.GameState
+		if bid == toolkit.Pass {
+			// pass -> current top bidder wins
+			lossState := gs.Copy()
 			if as.MaxPlayer >= 0 {
-				lossState = simulateLoss(gs, as, jewel, as.MaxPlayer)
-			} else {
-				lossState = gs.Copy()
+				lossState = toolkit.ApplyWin(gs, jewel, as.MaxPlayer, as.MaxValue)
 			}
 			val = evaluateState(lossState, me)
 		} else {
 			// bid -> simulate win
-			winState := simulateWin(gs, as, jewel, me, bid)
-			val = evaluateState(winState, me)
+			val = evaluateState(toolkit.ApplyWin(gs, jewel, me, bid), me)
 		}
 
 		if val > bestVal {
@@ -152,121 +126,16 @@ func (ai *MontplusAI) SelectAction(gs *game.GameState, as *game.AuctionState, je
 }
 
 func init() {
-	game.RegisterAI("MontplusAI Lv1", func() game.AI { return &MontplusAI{} })
-}
-
-// generateMinimalDominant enumerates minimal dominant bids
-func generateMinimalDominant(gs *game.GameState, as *game.AuctionState) [][3]int {
-	me := as.Turn
-	maxVal := as.MaxValue
-
-	// thresholds per color
-	T := make([][]int, 3)
-	for c := 0; c < 3; c++ {
-		set := map[int]bool{maxVal[c] + 1: true}
-		for j, active := range as.Active {
-			if !active || j == me {
-				continue
-			}
-			set[gs.Moneys[j][c]+1] = true
-		}
-		for v := range set {
-			T[c] = append(T[c], v)
-		}
-		sortInts(T[c])
-	}
-
-	// enumerate minimal
-	cands := make([][3]int, 0)
-	for _, r := range T[0] {
-		for _, g := range T[1] {
-			for _, b := range T[2] {
-				bid := [3]int{r, g, b}
-				if isDominantBid(bid, gs, as) {
-					cands = append(cands, bid)
-				}
-			}
-		}
-	}
-	return filterMinimal(cands)
-}
-
-// isDominantBid checks if bid exceeds each active opponent in at least one color
-func isDominantBid(bid [3]int, gs *game.GameState, as *game.AuctionState) bool {
-	me := as.Turn
-	for j, active := range as.Active {
-		if !active || j == me {
-			continue
-		}
-		exceeds := false
-		for c := 0; c < 3; c++ {
-			if bid[c] > gs.Moneys[j][c] {
-				exceeds = true
-				break
-			}
-		}
-		if !exceeds {
-			return false
-		}
-	}
-	return true
-}
-
-// filterMinimal removes bids dominated by another candidate
-func filterMinimal(cands [][3]int) [][3]int {
-	res := make([][3]int, 0)
-	for i, a := range cands {
-		keep := true
-		for j, b := range cands {
-			if i == j {
-				continue
-			}
-			allLeq, anyLess := true, false
-			for c := 0; c < 3; c++ {
-				if b[c] > a[c] {
-					allLeq = false
-					break
-				}
-				if b[c] < a[c] {
-					anyLess = true
-				}
-			}
-			if allLeq && anyLess {
-				keep = false
-				break
-			}
-		}
-		if keep {
-			res = append(res, a)
-		}
-	}
-	return res
-}
-
-// simulateWin applies state changes when me wins
-func simulateWin(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel, me int, bid [3]int) *game.GameState {
-	next := gs.Copy()
-	for c := 0; c < 3; c++ {
-		next.Moneys[me][c] -= bid[c]
-	}
-	next.Scores[me] += jewel.Point
-	for c := 0; c < 3; c++ {
-		next.Incomes[me][c] += jewel.Income[c]
-	}
-	return next
-}
-
-// simulateLoss applies state changes when opp wins
-func simulateLoss(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel, opp int) *game.GameState {
-	next := gs.Copy()
-	for c := 0; c < 3; c++ {
-		next.Moneys[opp][c] -= as.MaxValue[c]
-	}
-	next.Scores[opp] += jewel.Point
-	for c := 0; c < 3; c++ {
-		next.Incomes[opp][c] += jewel.Income[c]
-	}
-	return next
+	game.Register(game.AIInfo{
+		ID:            "montplusai",
+		Name:          "MontplusAI Lv1",
+		Author:        "MONTplusa",
+		Version:       "1.0",
+		Description:   "支払意思額・最小支配入札・ランダム入札を候補に、落札/非落札後の盤面評価で選ぶ",
+		Tags:          []string{game.TagBaseline},
+		Params:        params,
+		NewWithParams: func(p game.Params) game.AI { return New(p) },
+	})
 }
 
 // evaluateState scores a GameState for player me with dynamic weighted sums
@@ -327,16 +196,3 @@ func evaluateState(gs *game.GameState, me int) float64 {
 
 	return wScore*scoreSum + wCoinNow*coinNowSum + wCoinNext*coinNextSum + wIncome*incomeSum
 }
-
-// sortInts sorts small integer slices using insertion sort
-func sortInts(a []int) {
-	for i := 1; i < len(a); i++ {
-		key := a[i]
-		j := i - 1
-		for j >= 0 && a[j] > key {
-			a[j+1] = a[j]
-			j--
-		}
-		a[j+1] = key
-	}
-}
@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/montplusa/auction-game/ai/toolkit"
 	"github.com/montplusa/auction-game/game"
 )
 
@@ -60,144 +61,30 @@ func (ai *MontplusAI2) SelectAction(gs *game.GameState, as *game.AuctionState, j
 	}
 
 	// minimal dominant bids
-	doms := generateMinimalDominant(gs, as)
-	for _, b := range doms {
-		valid := true
-		for c := 0; c < 3; c++ {
-			if b[c] > budgets[c] {
-				valid = false
-				break
-			}
-		}
-		if valid {
+	for _, b := range toolkit.MinimalDominantBids(gs, as) {
+		if toolkit.Affordable(b, budgets) {
 			candidates = append(candidates, b)
 		}
 	}
 
 	// random bids (up to 100 candidates in [maxVal..budgets])
 	rand.Seed(time.Now().UnixNano())
-	randBids := make([][3]int, 0)
-	tries := 0
-	for len(randBids) < 100 && tries < 1000 {
-		tries++
-		var rb [3]int
-		ok := true
-		for c := 0; c < 3; c++ {
-			lb, ub := maxVal[c], budgets[c]
-			if ub < lb {
-				ok = false
-				break
-			}
-			rb[c] = lb + rand.Intn(ub-lb+1)
-		}
-		if ok {
-			randBids = append(randBids, rb)
-		}
-	}
-	for _, rb := range randBids {
-		candidates = append(candidates, rb)
-	}
-
-	// dedupe
-	seen := make(map[[3]int]bool)
-	finalCands := make([][3]int, 0)
-	for _, b := range candidates {
-		if !seen[b] {
-			seen[b] = true
-			finalCands = append(finalCands, b)
-		}
-	}
+	candidates = append(candidates, toolkit.RandomBids(maxVal, budgets, 100, toolkit.Uniform)...)
 
 	// 3. Evaluate candidates deterministically: pass->loss, bid->win
+	ev := toolkit.EvaluatorFunc(evaluateState)
 	bestVal := math.Inf(-1)
-	bestBid := [3]int{0, 0, 0}
-	for _, bid := range finalCands {
-		// skip non-pass bids that don't exceed current max
-		if bid != [3]int{0, 0, 0} {
-			exceed := false
-			for c := 0; c < 3; c++ {
-				if bid[c] > maxVal[c] {
-					exceed = true
-					break
-				}
-			}
-			if !exceed {
-				continue
-			}
-		}
-
+	bestBid := toolkit.Pass
+	for _, bid := range toolkit.Dedupe(candidates) {
 		var val float64
-		if bid == [3]int{0, 0, 0} {
-			// pass -> simulate loss
-			var lossState *game.GameState
-			lossState = gs.Copy()
-			minv := evaluateState(lossState, me)
-			for i := range gs.Scores {
-				if i == me {
-					continue
-				}
-				if !as.Active[i] {
-					continue
-				}
-				greater := false
-				ok := true
-				for c := 0; c < 3; c++ {
-					if as.MaxValue[c] < gs.Moneys[i][c] {
-						greater = true
-					}
-					if as.MaxValue[c] > gs.Moneys[i][c] {
-						ok = false
-						break
-					}
-				}
-				if !ok {
-					continue
-				}
-				if !greater && i != as.MaxPlayer {
-					continue
-				}
-				lossState = simulateLoss(gs, as, jewel, i, as.MaxValue)
-				v := evaluateState(lossState, me)
-				if v < minv {
-					minv = v
-				}
-			}
-			val = minv
+		if bid == toolkit.Pass {
+			// pass -> worst case over opponents taking it at the current max
+			val = toolkit.PassValue(gs, as, jewel, me, ev)
+		} else if toolkit.Raises(bid, maxVal) {
+			// bid -> worst case of winning or being outbid
+			val = toolkit.BidValue(gs, as, jewel, me, bid, ev)
 		} else {
-			// bid -> simulate win
-			newState := simulateWin(gs, as, jewel, me, bid)
-			minv := evaluateState(newState, me)
-			for i := range gs.Scores {
-				if i == me {
-					continue
-				}
-				if !as.Active[i] {
-					continue
-				}
-				greater := false
-				ok := true
-				for c := 0; c < 3; c++ {
-					if bid[c] < gs.Moneys[i][c] {
-						greater = true
-					}
-					if bid[c] > gs.Moneys[i][c] {
-						ok = false
-						break
-					}
-				}
-				if !ok {
-					continue
-				}
-				if !greater {
-					continue
-				}
-				newState = simulateLoss(gs, as, jewel, i, bid)
-				v := evaluateState(newState, me)
-				if v < minv {
-					minv = v
-				}
-			}
-			val = minv
+			continue
 		}
 
 		if val > bestVal {
@@ -210,131 +97,15 @@ func (ai *MontplusAI2) SelectAction(gs *game.GameState, as *game.AuctionState, j
 }
 
 func init() {
-	game.RegisterAI("MontplusAI Lv2", func() game.AI { return &MontplusAI2{} })
-}
-
-// generateMinimalDominant enumerates minimal dominant bids
-func generateMinimalDominant(gs *game.GameState, as *game.AuctionState) [][3]int {
-	me := as.Turn
-	maxVal := as.MaxValue
-
-	// thresholds per color
-	T := make([][]int, 3)
-	for c := 0; c < 3; c++ {
-		set := map[int]bool{maxVal[c] + 1: true, maxVal[c]: true}
-		for j, active := range as.Active {
-			if !active || j == me {
-				continue
-			}
-			set[gs.Moneys[j][c]+1] = true
-		}
-		for v := range set {
-			T[c] = append(T[c], v)
-		}
-		sortInts(T[c])
-	}
-
-	// enumerate minimal
-	cands := make([][3]int, 0)
-	for _, r := range T[0] {
-		for _, g := range T[1] {
-			for _, b := range T[2] {
-				bid := [3]int{r, g, b}
-				same := true
-				for i := 0; i < 3; i++ {
-					if bid[i] != maxVal[i] {
-						same = false
-						break
-					}
-				}
-				if same {
-					continue
-				}
-				if isDominantBid(bid, gs, as) {
-					cands = append(cands, bid)
-				}
-			}
-		}
-	}
-	return filterMinimal(cands)
-}
-
-// isDominantBid checks if bid exceeds each active opponent in at least one color
-func isDominantBid(bid [3]int, gs *game.GameState, as *game.AuctionState) bool {
-	me := as.Turn
-	for j, active := range as.Active {
-		if !active || j == me {
-			continue
-		}
-		exceeds := false
-		for c := 0; c < 3; c++ {
-			if bid[c] > gs.Moneys[j][c] {
-				exceeds = true
-				break
-			}
-		}
-		if !exceeds {
-			return false
-		}
-	}
-	return true
-}
-
-// filterMinimal removes bids dominated by another candidate
-func filterMinimal(cands [][3]int) [][3]int {
-	res := make([][3]int, 0)
-	for i, a := range cands {
-		keep := true
-		for j, b := range cands {
-			if i == j {
-				continue
-			}
-			allLeq, anyLess := true, false
-			for c := 0; c < 3; c++ {
-				if b[c] > a[c] {
-					allLeq = false
-					break
-				}
-				if b[c] < a[c] {
-					anyLess = true
-				}
-			}
-			if allLeq && anyLess {
-				keep = false
-				break
-			}
-		}
-		if keep {
-			res = append(res, a)
-		}
-	}
-	return res
-}
-
-// simulateWin applies state changes when me wins
-func simulateWin(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel, me int, bid [3]int) *game.GameState {
-	next := gs.Copy()
-	for c := 0; c < 3; c++ {
-		next.Moneys[me][c] -= bid[c]
-	}
-	next.Scores[me] += jewel.Point
-	for c := 0; c < 3; c++ {
-		next.Incomes[me][c] += jewel.Income[c]
-	}
-	return next
-}
-
-// simulateLoss applies state changes when opp wins
-func simulateLoss(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel, opp int, bid [3]int) *game.GameState {
-	next := gs.Copy()
-	for c := 0; c < 3; c++ {
-		next.Moneys[opp][c] -= bid[c]
-	}
-	next.Scores[opp] += jewel.Point
-	for c := 0; c < 3; c++ {
-		next.Incomes[opp][c] += jewel.Income[c]
-	}
-	return next
+	game.Register(game.AIInfo{
+		ID:          "montplusai2",
+		Name:        "MontplusAI Lv2",
+		Author:      "MONTplusa",
+		Version:     "1.0",
+		Description: "Lv1 の評価を、他の入札者が落札する場合の最悪値で行う",
+		Tags:        []string{game.TagBaseline},
+		New:         func() game.AI { return &MontplusAI2{} },
+	})
 }
 
 // evaluateState scores a GameState for player me with dynamic weighted sums
@@ -395,16 +166,3 @@ func evaluateState(gs *game.GameState, me int) float64 {
 
 	return wScore*scoreSum + wCoinNow*coinNowSum + wCoinNext*coinNextSum + wIncome*incomeSum
 }
-
-// sortInts sorts small integer slices using insertion sort
-func sortInts(a []int) {
-	for i := 1; i < len(a); i++ {
-		key := a[i]
-		j := i - 1
-		for j >= 0 && a[j] > key {
-			a[j+1] = a[j]
-			j--
-		}
-		a[j+1] = key
-	}
-}
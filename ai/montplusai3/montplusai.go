@@ -5,11 +5,33 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/montplusa/auction-game/ai/toolkit"
 	"github.com/montplusa/auction-game/game"
 )
 
 // MontplusAI3 implements a bidding AI using minimal dominant bids, +1-step, WTP, random bids, and deterministic evaluation.
-type MontplusAI3 struct{}
+type MontplusAI3 struct {
+	alpha      float64 // WTP: 得点 1 あたりの価値
+	beta       float64 // WTP: 残りフェーズ 1 回分の収入 1 あたりの価値
+	candidates int     // ランダム入札候補の数
+}
+
+// params are the tunable parameters of MontplusAI3.
+var params = []game.ParamSpec{
+	{Name: "alpha", Kind: game.ParamFloat, Default: 1.2, Min: 0, Max: 10, Description: "WTP の得点係数"},
+	{Name: "beta", Kind: game.ParamFloat, Default: 0.8, Min: 0, Max: 10, Description: "WTP の収入係数"},
+	{Name: "candidates", Kind: game.ParamInt, Default: 100, Min: 0, Max: 1000, Description: "ランダム入札候補の数"},
+}
+
+// New returns a MontplusAI3 configured with p; missing parameters take their defaults.
+func New(p game.Params) *MontplusAI3 {
+	p = p.WithDefaults(params)
+	return &MontplusAI3{
+		alpha:      p.Float("alpha"),
+		beta:       p.Float("beta"),
+		candidates: p.Int("candidates"),
+	}
+}
 
 func (ai *MontplusAI3) GetName() string {
 	return "MontplusAI Lv3"
@@ -20,18 +42,9 @@ func (ai *MontplusAI3) SelectAction(gs *game.GameState, as *game.AuctionState, j
 	me := as.Turn
 	budgets := gs.Moneys[me]
 	maxVal := as.MaxValue
-	phaseLeft := 10 - gs.Phase
 
 	// 1. Calculate Willingness to Pay (WTP)
-	alpha, beta := 1.2, 0.8
-	scoreVal := alpha * float64(jewel.Point)
-	incomeVal := 0.0
-	for _, inc := range jewel.Income {
-		if inc > 0 {
-			incomeVal = beta * float64(inc) * float64(phaseLeft)
-		}
-	}
-	wtp := int(math.Round(scoreVal + incomeVal))
+	wtp := ai.willingness(gs, jewel, me)
 
 	// 2. Generate candidate bids (including pass)
 	candidates := make([][3]int, 0)
@@ -60,287 +73,151 @@ func (ai *MontplusAI3) SelectAction(gs *game.GameState, as *game.AuctionState, j
 	}
 
 	// minimal dominant bids
-	doms := generateMinimalDominant(gs, as)
-	for _, b := range doms {
-		valid := true
-		for c := 0; c < 3; c++ {
-			if b[c] > budgets[c] {
-				valid = false
-				break
-			}
-		}
-		if valid {
+	for _, b := range toolkit.MinimalDominantBids(gs, as) {
+		if toolkit.Affordable(b, budgets) {
 			candidates = append(candidates, b)
 		}
 	}
 
-	// random bids (up to 100 candidates in [maxVal..budgets])
+	// random bids (up to ai.candidates candidates in [maxVal..budgets])
 	rand.Seed(time.Now().UnixNano())
-	randBids := make([][3]int, 0)
-	tries := 0
-	for len(randBids) < 100 && tries < 1000 {
-		tries++
-		var rb [3]int
-		ok := true
-		for c := 0; c < 3; c++ {
-			lb, ub := maxVal[c], budgets[c]
-			if ub < lb {
-				ok = false
-				break
-			}
-			rb[c] = lb + rand.Intn(ub-lb+1)
-		}
-		if ok {
-			randBids = append(randBids, rb)
-		}
-	}
-	for _, rb := range randBids {
-		candidates = append(candidates, rb)
-	}
-
-	// dedupe
-	seen := make(map[[3]int]bool)
-	finalCands := make([][3]int, 0)
-	for _, b := range candidates {
-		if !seen[b] {
-			seen[b] = true
-			finalCands = append(finalCands, b)
-		}
-	}
+	candidates = append(candidates, toolkit.RandomBids(maxVal, budgets, ai.candidates, toolkit.Uniform)...)
 
 	// 3. Evaluate candidates deterministically: pass->loss, bid->win
+	ev := toolkit.EvaluatorFunc(evaluateState)
 	bestVal := math.Inf(-1)
-	bestBid := [3]int{0, 0, 0}
-	// fmt.Println(gs.Phase, gs.Round)
-	for _, bid := range finalCands {
-		// skip non-pass bids that don't exceed current max
-		if bid != [3]int{0, 0, 0} {
-			exceed := false
-			greater := true
-			for c := 0; c < 3; c++ {
-				if bid[c] > maxVal[c] {
-					exceed = true
-				}
-				if bid[c] < maxVal[c] {
-					greater = false
-					break
-				}
-			}
-			if !exceed || !greater {
-				continue
-			}
-		}
-
+	bestBid := toolkit.Pass
+	for _, bid := range toolkit.Dedupe(candidates) {
 		var val float64
-		if bid == [3]int{0, 0, 0} {
-			// pass -> simulate loss
-			var lossState *game.GameState
-			lossState = gs.Copy()
-			minv := evaluateState(lossState, me)
-			for i := range gs.Scores {
-				if i == me {
-					continue
-				}
-				if !as.Active[i] {
-					continue
-				}
-				greater := false
-				ok := true
-				for c := 0; c < 3; c++ {
-					if as.MaxValue[c] < gs.Moneys[i][c] {
-						greater = true
-					}
-					if as.MaxValue[c] > gs.Moneys[i][c] {
-						ok = false
-						break
-					}
-				}
-				if !ok {
-					continue
-				}
-				if !greater && i != as.MaxPlayer {
-					continue
-				}
-				lossState = simulateLoss(gs, as, jewel, i, as.MaxValue)
-				v := evaluateState(lossState, me)
-				if v < minv {
-					minv = v
-				}
-			}
-			val = minv
+		if bid == toolkit.Pass {
+			// pass -> worst case over opponents taking it at the current max
+			val = toolkit.PassValue(gs, as, jewel, me, ev)
+		} else if toolkit.Raises(bid, maxVal) {
+			// bid -> worst case of winning or being outbid
+			val = toolkit.BidValue(gs, as, jewel, me, bid, ev)
 		} else {
-			// bid -> simulate win
-			newState := simulateWin(gs, as, jewel, me, bid)
-			minv := evaluateState(newState, me)
-			for i := range gs.Scores {
-				if i == me {
-					continue
-				}
-				if !as.Active[i] {
-					continue
-				}
-				greater := false
-				ok := true
-				for c := 0; c < 3; c++ {
-					if bid[c] < gs.Moneys[i][c] {
-						greater = true
-					}
-					if bid[c] > gs.Moneys[i][c] {
-						ok = false
-						break
-					}
-				}
-				if !ok {
-					continue
-				}
-				if !greater {
-					continue
-				}
-				newState = simulateLoss(gs, as, jewel, i, bid)
-				v := evaluateState(newState, me)
-				if v < minv {
-					minv = v
-				}
-			}
-			val = minv
+			continue
 		}
 
 		if val > bestVal {
 			bestVal = val
 			bestBid = bid
 		}
-		// fmt.Println(bid, val)
 	}
 
 	return bestBid
 }
 
-func init() {
-	game.RegisterAI("MontplusAI Lv3", func() game.AI { return &MontplusAI3{} })
+// willingness is the number of coins the jewel is worth to player me: see value, with the
+// set-collection bonus the jewel would add.
+func (ai *MontplusAI3) willingness(gs *game.GameState, jewel *game.Jewel, me int) int {
+	return ai.value(gs, jewel, ai.synergy(gs, jewel, me))
 }
 
-// generateMinimalDominant enumerates minimal dominant bids
-func generateMinimalDominant(gs *game.GameState, as *game.AuctionState) [][3]int {
-	me := as.Turn
-	maxVal := as.MaxValue
-
-	// thresholds per color
-	T := make([][]int, 3)
-	for c := 0; c < 3; c++ {
-		set := map[int]bool{maxVal[c] + 1: true, maxVal[c]: true}
-		for j, active := range as.Active {
-			if !active || j == me {
-				continue
-			}
-			set[gs.Moneys[j][c]+1] = true
-		}
-		for v := range set {
-			T[c] = append(T[c], v)
+// value is the number of coins the jewel is worth: alpha per point, including bonus points
+// that depend on owning it, plus beta per coin of income over the remaining phases.
+func (ai *MontplusAI3) value(gs *game.GameState, jewel *game.Jewel, bonus int) int {
+	phaseLeft := 10 - gs.Phase
+	scoreVal := ai.alpha * float64(jewel.Point+bonus)
+	incomeVal := 0.0
+	for _, inc := range jewel.Income {
+		if inc > 0 {
+			incomeVal = ai.beta * float64(inc) * float64(phaseLeft)
 		}
-		sortInts(T[c])
 	}
+	return int(math.Round(scoreVal + incomeVal))
+}
 
-	// enumerate minimal
-	cands := make([][3]int, 0)
-	for _, r := range T[0] {
-		for _, g := range T[1] {
-			for _, b := range T[2] {
-				bid := [3]int{r, g, b}
-				same := true
-				for i := 0; i < 3; i++ {
-					if bid[i] != maxVal[i] {
-						same = false
-						break
-					}
-				}
-				if same {
-					continue
-				}
-				if isDominantBid(bid, gs, as) {
-					cands = append(cands, bid)
-				}
-			}
-		}
+// synergy returns the set-collection bonus that owning jewel would add for player me (0 without
+// a bonus rule).
+func (ai *MontplusAI3) synergy(gs *game.GameState, jewel *game.Jewel, me int) int {
+	scorer, _ := game.LookupScorer(gs.Rules.Bonus)
+	if scorer == nil || me >= len(gs.Owned) {
+		return 0
 	}
-	return filterMinimal(cands)
+	owned := gs.Owned[me]
+	with := append(append([]game.Jewel(nil), owned...), *jewel)
+	return scorer.Bonus(with) - scorer.Bonus(owned)
 }
 
-// isDominantBid checks if bid exceeds each active opponent in at least one color
-func isDominantBid(bid [3]int, gs *game.GameState, as *game.AuctionState) bool {
-	me := as.Turn
-	for j, active := range as.Active {
-		if !active || j == me {
-			continue
-		}
-		exceeds := false
-		for c := 0; c < 3; c++ {
-			if bid[c] > gs.Moneys[j][c] {
-				exceeds = true
-				break
-			}
-		}
-		if !exceeds {
-			return false
-		}
+// SelectSealedBid implements game.SealedBidder by spending sealedTarget coins on the colors
+// that rank highest under the comparison rule.
+func (ai *MontplusAI3) SelectSealedBid(gs *game.GameState, jewel *game.Jewel, me int) [3]int {
+	target := ai.sealedTarget(gs, jewel, me)
+	if target <= 0 {
+		return toolkit.Pass
 	}
-	return true
+	return toolkit.SpendFor(gs.Rules, gs.Moneys[me], target)
 }
 
-// filterMinimal removes bids dominated by another candidate
-func filterMinimal(cands [][3]int) [][3]int {
-	res := make([][3]int, 0)
-	for i, a := range cands {
-		keep := true
-		for j, b := range cands {
-			if i == j {
-				continue
-			}
-			allLeq, anyLess := true, false
-			for c := 0; c < 3; c++ {
-				if b[c] > a[c] {
-					allLeq = false
-					break
-				}
-				if b[c] < a[c] {
-					anyLess = true
-				}
-			}
-			if allLeq && anyLess {
-				keep = false
-				break
-			}
+// AcceptAsk implements game.DutchBidder. A Dutch auction is a first-price sealed-bid auction in
+// disguise, so it buys as soon as the asking price falls to its sealed bid.
+func (ai *MontplusAI3) AcceptAsk(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) bool {
+	ask := as.Asking[0] + as.Asking[1] + as.Asking[2]
+	return ask <= ai.sealedTarget(gs, jewel, as.Turn)
+}
+
+// SelectExchanges implements game.Exchanger: it evens out its richest and poorest colors, since
+// a bid can only raise colors it holds.
+func (ai *MontplusAI3) SelectExchanges(gs *game.GameState, jewel *game.Jewel, me int) []game.Exchange {
+	return toolkit.BalanceExchange(gs.Moneys[me], gs.Rules.Exchange)
+}
+
+// SelectSale implements game.Seller: it sells the jewel whose resale price most exceeds its
+// value (including the set-collection bonus lost with it), counting coins as worthless in the
+// final phase.
+func (ai *MontplusAI3) SelectSale(gs *game.GameState, me int) int {
+	scorer, _ := game.LookupScorer(gs.Rules.Bonus)
+	best, bestGain := -1, 0
+	for k, j := range gs.Owned[me] {
+		if !gs.CanSell(me, k) {
+			continue
 		}
-		if keep {
-			res = append(res, a)
+		coins := 0
+		if gs.Phase < game.NumPhases {
+			price := gs.ResalePrice(j)
+			coins = price[0] + price[1] + price[2]
+		}
+		lost := 0
+		if scorer != nil {
+			without := append(append([]game.Jewel(nil), gs.Owned[me][:k]...), gs.Owned[me][k+1:]...)
+			lost = scorer.Bonus(gs.Owned[me]) - scorer.Bonus(without)
+		}
+		if gain := coins - ai.value(gs, &j, lost); gain > bestGain {
+			best, bestGain = k, gain
 		}
 	}
-	return res
+	return best
 }
 
-// simulateWin applies state changes when me wins
-func simulateWin(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel, me int, bid [3]int) *game.GameState {
-	next := gs.Copy()
-	for c := 0; c < 3; c++ {
-		next.Moneys[me][c] -= bid[c]
+// sealedTarget returns the coins to bid without seeing rival bids. Prices follow the coins in
+// play, so it scales the willingness to pay by the mean coins per player (75 coins being par),
+// and shades it by (N-1)/N when paying its own bid (truthful bidding is optimal when the winner
+// pays the second-highest bid).
+func (ai *MontplusAI3) sealedTarget(gs *game.GameState, jewel *game.Jewel, me int) int {
+	N := len(gs.Scores)
+	coins := 0
+	for _, m := range gs.Moneys {
+		coins += m[0] + m[1] + m[2]
 	}
-	next.Scores[me] += jewel.Point
-	for c := 0; c < 3; c++ {
-		next.Incomes[me][c] += jewel.Income[c]
+	scale := float64(coins) / float64(N) / 75
+	if gs.Rules.Payment != game.PaymentSecond {
+		scale *= float64(N-1) / float64(N)
 	}
-	return next
+	return int(math.Round(float64(ai.willingness(gs, jewel, me)) * scale))
 }
 
-// simulateLoss applies state changes when opp wins
-func simulateLoss(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel, opp int, bid [3]int) *game.GameState {
-	next := gs.Copy()
-	for c := 0; c < 3; c++ {
-		next.Moneys[opp][c] -= bid[c]
-	}
-	next.Scores[opp] += jewel.Point
-	for c := 0; c < 3; c++ {
-		next.Incomes[opp][c] += jewel.Income[c]
-	}
-	return next
+func init() {
+	game.Register(game.AIInfo{
+		ID:            "montplusai3",
+		Name:          "MontplusAI Lv3",
+		Author:        "MONTplusa",
+		Version:       "1.0",
+		Description:   "Lv2 の盤面評価をフェーズ・ラウンドの進行に応じて重み付けしたもの",
+		Tags:          []string{game.TagBaseline},
+		Params:        params,
+		NewWithParams: func(p game.Params) game.AI { return New(p) },
+	})
 }
 
 // evaluateState scores a GameState for player me with dynamic weighted sums
@@ -403,16 +280,3 @@ func evaluateState(gs *game.GameState, me int) float64 {
 
 	return wScore*scoreSum + wCoinNow*coinNowSum + wCoinNext*coinNextSum + wIncome*incomeSum
 }
-
-// sortInts sorts small integer slices using insertion sort
-func sortInts(a []int) {
-	for i := 1; i < len(a); i++ {
-		key := a[i]
-		j := i - 1
-		for j >= 0 && a[j] > key {
-			a[j+1] = a[j]
-			j--
-		}
-		a[j+1] = key
-	}
-}
@@ -0,0 +1,104 @@
+package nn
+
+import "github.com/montplusa/auction-game/game"
+
+// Feature layout. Everything is seen from player me and scaled to roughly [0,1], and opponents
+// are aggregated so that the vector size does not depend on the number of players.
+const (
+	// StateSize is the length of EncodeState's output.
+	StateSize = 33
+	// BidSize is the length of EncodeBid's output.
+	BidSize = 12
+)
+
+// Scales used to normalize raw quantities.
+const (
+	coinScale   = 30.0
+	incomeScale = 10.0
+	scoreScale  = 100.0
+)
+
+// EncodeState encodes the position of player me:
+//
+//	[0:4]   phase/10, round/3N, players/8, phases left/10
+//	[4:8]   jewel point/10, jewel income (R,G,B)/5
+//	[8:15]  my coins (R,G,B), my income (R,G,B), my score
+//	[15:22] max over opponents: coins (R,G,B), income (R,G,B), score
+//	[22:29] mean over opponents: coins (R,G,B), income (R,G,B), score
+//	[29:33] current max bid (R,G,B), whether I hold it
+func EncodeState(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel, me int) []float64 {
+	N := len(gs.Scores)
+	f := make([]float64, StateSize)
+	f[0] = float64(gs.Phase) / game.NumPhases
+	f[1] = float64(gs.Round) / float64(3*N)
+	f[2] = float64(N) / 8
+	f[3] = float64(game.NumPhases-gs.Phase) / game.NumPhases
+
+	f[4] = float64(jewel.Point) / 10
+	for c := 0; c < 3; c++ {
+		f[5+c] = float64(jewel.Income[c]) / 5
+	}
+
+	player := func(off, j int) {
+		for c := 0; c < 3; c++ {
+			f[off+c] = float64(gs.Moneys[j][c]) / coinScale
+			f[off+3+c] = float64(gs.Incomes[j][c]) / incomeScale
+		}
+		f[off+6] = float64(gs.Scores[j]) / scoreScale
+	}
+	player(8, me)
+
+	first := true
+	for j := 0; j < N; j++ {
+		if j == me {
+			continue
+		}
+		vals := [7]float64{}
+		for c := 0; c < 3; c++ {
+			vals[c] = float64(gs.Moneys[j][c]) / coinScale
+			vals[3+c] = float64(gs.Incomes[j][c]) / incomeScale
+		}
+		vals[6] = float64(gs.Scores[j]) / scoreScale
+		for i, v := range vals {
+			if first || v > f[15+i] {
+				f[15+i] = v
+			}
+			f[22+i] += v / float64(N-1)
+		}
+		first = false
+	}
+
+	for c := 0; c < 3; c++ {
+		f[29+c] = float64(as.MaxValue[c]) / coinScale
+	}
+	if as.MaxPlayer == me {
+		f[32] = 1
+	}
+	return f
+}
+
+// EncodeBid encodes a candidate bid of player me:
+//
+//	[0:3]   bid (R,G,B)
+//	[3:6]   raise over the current max (R,G,B)
+//	[6:9]   coins left after paying (R,G,B)
+//	[9]     1 for a pass
+//	[10]    jewel point/10 if bidding, else 0
+//	[11]    jewel income over the remaining phases/50 if bidding, else 0
+func EncodeBid(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel, me int, bid [3]int) []float64 {
+	f := make([]float64, BidSize)
+	if bid == [3]int{} {
+		f[9] = 1
+		return f
+	}
+	income := 0
+	for c := 0; c < 3; c++ {
+		f[c] = float64(bid[c]) / coinScale
+		f[3+c] = float64(bid[c]-as.MaxValue[c]) / coinScale
+		f[6+c] = float64(gs.Moneys[me][c]-bid[c]) / coinScale
+		income += jewel.Income[c]
+	}
+	f[10] = float64(jewel.Point) / 10
+	f[11] = float64(income*(game.NumPhases-gs.Phase)) / 50
+	return f
+}
@@ -0,0 +1,98 @@
+package nn
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// Layer is a fully connected layer: out = act(W·in + b).
+type Layer struct {
+	Weights    [][]float64 `json:"weights"` // [出力][入力]
+	Bias       []float64   `json:"bias"`
+	Activation string      `json:"activation"` // "relu", "tanh", "sigmoid" または "linear"
+}
+
+// Network is a feed-forward network stored as JSON so that weights trained elsewhere can be
+// dropped in. Inference is plain Go and also runs in the WASM build.
+type Network struct {
+	Layers []Layer `json:"layers"`
+}
+
+// LoadNetwork reads a network from a JSON file and checks its shapes.
+func LoadNetwork(path string) (*Network, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseNetwork(data)
+}
+
+// ParseNetwork decodes a network from JSON and checks its shapes.
+func ParseNetwork(data []byte) (*Network, error) {
+	n := &Network{}
+	if err := json.Unmarshal(data, n); err != nil {
+		return nil, err
+	}
+	if err := n.validate(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// InputSize returns the number of inputs the network expects.
+func (n *Network) InputSize() int {
+	if len(n.Layers) == 0 || len(n.Layers[0].Weights) == 0 {
+		return 0
+	}
+	return len(n.Layers[0].Weights[0])
+}
+
+func (n *Network) validate() error {
+	if len(n.Layers) == 0 {
+		return fmt.Errorf("nn: network has no layers")
+	}
+	in := n.InputSize()
+	for i, l := range n.Layers {
+		if len(l.Weights) == 0 || len(l.Bias) != len(l.Weights) {
+			return fmt.Errorf("nn: layer %d has %d rows and %d biases", i, len(l.Weights), len(l.Bias))
+		}
+		for _, row := range l.Weights {
+			if len(row) != in {
+				return fmt.Errorf("nn: layer %d expects %d inputs, row has %d", i, in, len(row))
+			}
+		}
+		if _, ok := activations[l.Activation]; !ok {
+			return fmt.Errorf("nn: layer %d has unknown activation %q", i, l.Activation)
+		}
+		in = len(l.Weights)
+	}
+	return nil
+}
+
+var activations = map[string]func(float64) float64{
+	"":        func(x float64) float64 { return x },
+	"linear":  func(x float64) float64 { return x },
+	"relu":    func(x float64) float64 { return math.Max(0, x) },
+	"tanh":    math.Tanh,
+	"sigmoid": func(x float64) float64 { return 1 / (1 + math.Exp(-x)) },
+}
+
+// Forward evaluates the network on in and returns the output layer.
+func (n *Network) Forward(in []float64) []float64 {
+	x := in
+	for _, l := range n.Layers {
+		act := activations[l.Activation]
+		out := make([]float64, len(l.Weights))
+		for o, row := range l.Weights {
+			s := l.Bias[o]
+			for i, w := range row {
+				s += w * x[i]
+			}
+			out[o] = act(s)
+		}
+		x = out
+	}
+	return x
+}
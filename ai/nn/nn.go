@@ -0,0 +1,102 @@
+// Package nn provides a bidding AI that scores candidate bids with a small feed-forward network,
+// together with the feature encoder used for its input.
+package nn
+
+import (
+	_ "embed"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/montplusa/auction-game/ai/toolkit"
+	"github.com/montplusa/auction-game/game"
+)
+
+// defaultNetwork is a hand-set linear network: the surplus of winning at the bid, valuing a point
+// at 1.2 coins and future income at 0.8, so the AI raises as cheaply as possible while winning
+// still pays off.
+//
+//go:embed default.json
+var defaultNetwork []byte
+
+// WeightsEnv names the environment variable that points the registered AI at another weights file.
+const WeightsEnv = "AUCTION_NN_WEIGHTS"
+
+// NNAI evaluates every candidate bid with Network on EncodeState ++ EncodeBid and plays the best.
+// The network must take StateSize+BidSize inputs; its first output is the score.
+type NNAI struct {
+	net *Network
+}
+
+// New returns an NNAI using net.
+func New(net *Network) *NNAI {
+	return &NNAI{net: net}
+}
+
+func (ai *NNAI) GetName() string {
+	return "NeuralNet"
+}
+
+func (ai *NNAI) SelectAction(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) [3]int {
+	me := as.Turn
+	money := gs.Moneys[me]
+
+	candidates := [][3]int{toolkit.Pass}
+	candidates = append(candidates, game.MinimalRaises(gs, as, me)...)
+	for _, b := range toolkit.MinimalDominantRaises(gs, as) {
+		if game.IsLegalBid(gs, as, me, b) {
+			candidates = append(candidates, b)
+		}
+	}
+	current := as.MaxValue[0] + as.MaxValue[1] + as.MaxValue[2]
+	for _, k := range []int{2, 4, 8, 16} {
+		if b := toolkit.RaiseTo(as.MaxValue, money, current+k); b != toolkit.Pass {
+			candidates = append(candidates, b)
+		}
+	}
+
+	state := EncodeState(gs, as, jewel, me)
+	in := make([]float64, StateSize+BidSize)
+	copy(in, state)
+	best, bestVal := toolkit.Pass, math.Inf(-1)
+	for _, bid := range toolkit.Dedupe(candidates) {
+		copy(in[StateSize:], EncodeBid(gs, as, jewel, me, bid))
+		if v := ai.net.Forward(in)[0]; v > bestVal {
+			best, bestVal = bid, v
+		}
+	}
+	return best
+}
+
+// loadNetwork returns the network named by WeightsEnv, or the embedded default when it is unset.
+func loadNetwork() (*Network, error) {
+	path := os.Getenv(WeightsEnv)
+	if path == "" {
+		return ParseNetwork(defaultNetwork)
+	}
+	net, err := LoadNetwork(path)
+	if err != nil {
+		return nil, fmt.Errorf("nn: %s: %v", WeightsEnv, err)
+	}
+	if net.InputSize() != StateSize+BidSize {
+		return nil, fmt.Errorf("nn: %s: network takes %d inputs, want %d", WeightsEnv, net.InputSize(), StateSize+BidSize)
+	}
+	return net, nil
+}
+
+// init registers the AI even if WeightsEnv names a weights file that cannot be used; the error is
+// then kept in AIInfo.Err, so that runs asking for NeuralNet fail when they resolve it instead of
+// silently playing with the default weights, and runs without it are unaffected.
+func init() {
+	net, err := loadNetwork()
+	game.Register(game.AIInfo{
+		ID:          "nn",
+		Name:        "NeuralNet",
+		Author:      "MONTplusa",
+		Version:     "1.0",
+		Description: "候補入札を順伝播ネットワークで評価して最良のものを選ぶ (重みは " + WeightsEnv + " の JSON ファイルで差し替え可、既定は手製の線形モデル)",
+		Tags:        []string{game.TagExperimental},
+		New:         func() game.AI { return New(net) },
+		Err:         err,
+	})
+}
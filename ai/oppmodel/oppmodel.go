@@ -0,0 +1,175 @@
+package oppmodel
+
+import (
+	"github.com/montplusa/auction-game/ai/toolkit"
+	"github.com/montplusa/auction-game/game"
+)
+
+// OpponentModeler learns during the match how much each opponent is willing to pay.
+//
+// Every opponent j is modelled as paying at most theta_j coins per point of jewel value, where the
+// value is the jewel's points plus its income over the remaining phases. Observed bids and winning
+// prices are averaged into theta_j; dropping out while the maximum was some price caps it. The AI
+// values the jewel itself at margin coins per point and, when it is the highest valuation at the
+// table, jumps straight to one coin above the highest predicted opponent willingness to pay (the
+// predicted second-highest valuation).
+type OpponentModeler struct {
+	incomeWorth float64 // 残りフェーズ 1 回分の収入 1 枚の得点換算
+	margin      float64 // 自分の支払意思額 (価値 1 あたりのコイン数)
+	prior       float64 // 相手の theta の初期値
+	lr          float64 // theta の学習率
+	spend       float64 // 1 回のオークションで使う所持コインの割合の上限
+
+	theta      []float64
+	lastAS     *game.AuctionState
+	lastJewel  *game.Jewel
+	lastPhase  int
+	lastActive []bool
+}
+
+// params are the tunable parameters of OpponentModeler.
+var params = []game.ParamSpec{
+	{Name: "income", Kind: game.ParamFloat, Default: 0.8, Min: 0, Max: 5, Description: "収入 1 枚 × 残りフェーズ 1 回の得点換算"},
+	{Name: "margin", Kind: game.ParamFloat, Default: 2, Min: 0, Max: 10, Description: "自分の支払意思額 (価値 1 あたりのコイン数)"},
+	{Name: "prior", Kind: game.ParamFloat, Default: 0.5, Min: 0, Max: 10, Description: "相手の支払意思額の初期値"},
+	{Name: "spend", Kind: game.ParamFloat, Default: 0.4, Min: 0, Max: 1, Description: "1 回のオークションで使う所持コインの割合の上限"},
+	{Name: "lr", Kind: game.ParamFloat, Default: 0.3, Min: 0, Max: 1, Description: "学習率"},
+}
+
+// New returns an OpponentModeler configured with p; missing parameters take their defaults.
+func New(p game.Params) *OpponentModeler {
+	p = p.WithDefaults(params)
+	return &OpponentModeler{
+		incomeWorth: p.Float("income"),
+		margin:      p.Float("margin"),
+		prior:       p.Float("prior"),
+		lr:          p.Float("lr"),
+		spend:       p.Float("spend"),
+	}
+}
+
+func (ai *OpponentModeler) GetName() string {
+	return "OpponentModeler"
+}
+
+// value returns the value of jewel in points when sold in phase.
+func (ai *OpponentModeler) value(jewel *game.Jewel, phase int) float64 {
+	income := 0
+	for _, inc := range jewel.Income {
+		income += inc
+	}
+	return float64(jewel.Point) + ai.incomeWorth*float64(income*(game.NumPhases-phase))
+}
+
+// maxRatio caps single observations so that a large bid on a nearly worthless jewel does not
+// blow the estimate up.
+const maxRatio = 3
+
+// observe moves theta[j] toward ratio. Bids and prices (lower=true) are taken as samples of the
+// opponent's typical willingness to pay; a drop-out only pulls the estimate down.
+func (ai *OpponentModeler) observe(j int, ratio float64, lower bool) {
+	if ratio > maxRatio {
+		ratio = maxRatio
+	}
+	if lower || ratio < ai.theta[j] {
+		ai.theta[j] += ai.lr * (ratio - ai.theta[j])
+	}
+}
+
+// update learns from what happened since the previous call.
+func (ai *OpponentModeler) update(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) {
+	me := as.Turn
+	if as != ai.lastAS {
+		// 前のオークションの落札者と支払い額は、その AuctionState に記録されている
+		if prev := ai.lastAS; prev != nil && prev.MaxPlayer >= 0 && prev.MaxPlayer != me {
+			price := prev.Price[0] + prev.Price[1] + prev.Price[2]
+			if v := ai.value(ai.lastJewel, ai.lastPhase); v >= 1 {
+				ai.observe(prev.MaxPlayer, float64(price)/v, true)
+			}
+		}
+		ai.lastActive = nil
+	}
+
+	v := ai.value(jewel, gs.Phase)
+	if v >= 1 {
+		price := float64(as.MaxValue[0] + as.MaxValue[1] + as.MaxValue[2])
+		for j, active := range as.Active {
+			if j == me {
+				continue
+			}
+			wasActive := ai.lastActive == nil || ai.lastActive[j]
+			switch {
+			case wasActive && !active:
+				// 現在の最高額以下で降りた
+				ai.observe(j, price/v, false)
+			case j == as.MaxPlayer:
+				// 現在の最高額を提示している
+				ai.observe(j, price/v, true)
+			}
+		}
+	}
+
+	ai.lastAS = as
+	ai.lastJewel = jewel
+	ai.lastPhase = gs.Phase
+	ai.lastActive = append(ai.lastActive[:0], as.Active...)
+}
+
+func (ai *OpponentModeler) SelectAction(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) [3]int {
+	me := as.Turn
+	if ai.theta == nil {
+		ai.theta = make([]float64, len(gs.Scores))
+		for j := range ai.theta {
+			ai.theta[j] = ai.prior
+		}
+	}
+	ai.update(gs, as, jewel)
+
+	money := gs.Moneys[me]
+	v := ai.value(jewel, gs.Phase)
+	mine := ai.margin * v
+	if limit := ai.spend * float64(money[0]+money[1]+money[2]); mine > limit {
+		mine = limit
+	}
+
+	// 相手の予測支払意思額の最大値 (= 自分が最高なら 2 番目の評価額)
+	second := 0.0
+	for j, active := range as.Active {
+		if j == me || !active {
+			continue
+		}
+		w := ai.theta[j] * v
+		if total := float64(gs.Moneys[j][0] + gs.Moneys[j][1] + gs.Moneys[j][2]); w > total {
+			w = total
+		}
+		if w > second {
+			second = w
+		}
+	}
+	if mine <= second {
+		return toolkit.Pass
+	}
+
+	current := as.MaxValue[0] + as.MaxValue[1] + as.MaxValue[2]
+	target := int(second) + 1
+	if target <= current {
+		target = current + 1
+	}
+	if float64(target) > mine {
+		return toolkit.Pass
+	}
+	return toolkit.RaiseTo(as.MaxValue, money, target)
+}
+
+func init() {
+	game.Register(game.AIInfo{
+		ID:            "oppmodel",
+		Name:          "OpponentModeler",
+		Author:        "MONTplusa",
+		Version:       "1.0",
+		Description:   "対局中に相手ごとの支払意思額 (宝石の価値あたりのコイン数) を入札・降り・落札額から学習し、予測される 2 番目の評価額の直上に入札する",
+		Tags:          []string{game.TagExperimental},
+		Params:        params,
+		NewWithParams: func(p game.Params) game.AI { return New(p) },
+	})
+}
@@ -14,34 +14,29 @@ func (ai *RandomAI) GetName() string {
 	return "RandomAI"
 }
 
-// SelectAction returns either a pass ([0,0,0]) or a random valid bid.
-// It randomly selects one coin color to increase above the current max.
+// SelectAction returns either a pass ([0,0,0]) or a bid drawn uniformly from all legal bids.
 func (ai *RandomAI) SelectAction(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) [3]int {
-	player := as.Turn
-	maxVal := as.MaxValue
-	money := gs.Moneys[player]
-
 	// 50% chance to pass
 	if rand.Float64() < 0.5 {
 		return [3]int{0, 0, 0}
 	}
 
-	bid := [3]int{}
-	for c := 0; c < 3; c++ {
-		// If insufficient funds, pass
-		if money[c] < maxVal[c] {
-			return [3]int{0, 0, 0}
-		}
-
-		// Generate a bid: match maxVal on all, exceed on chosen color
-		// Choose an amount between maxVal[c]+1 and money[c]
-		rangeMax := money[c] - maxVal[c]
-		amount := rand.Intn(rangeMax+1) + maxVal[c]
-		bid[c] = amount
+	// If no legal bid exists, pass
+	bid, ok := game.RandomLegalBid(nil, gs, as, as.Turn)
+	if !ok {
+		return [3]int{0, 0, 0}
 	}
 	return bid
 }
 
 func init() {
-	game.RegisterAI("RandomAI", func() game.AI { return &RandomAI{} })
+	game.Register(game.AIInfo{
+		ID:          "random",
+		Name:        "RandomAI",
+		Author:      "MONTplusa",
+		Version:     "1.0",
+		Description: "半々の確率で降りるか、合法な入札から一様ランダムに選ぶ",
+		Tags:        []string{game.TagBaseline},
+		New:         func() game.AI { return &RandomAI{} },
+	})
 }
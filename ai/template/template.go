@@ -17,5 +17,13 @@ func (ai *TemplateAI) SelectAction(gs *game.GameState, as *game.AuctionState, je
 }
 
 func init() {
-	game.RegisterAI("TemplateAI", func() game.AI { return &TemplateAI{} })
+	// ID は変更しないこと。Name は GetName と揃える
+	game.Register(game.AIInfo{
+		ID:          "template",
+		Name:        "TemplateAI",
+		Version:     "0.1",
+		Description: "常に降りる雛形",
+		Tags:        []string{game.TagExperimental},
+		New:         func() game.AI { return &TemplateAI{} },
+	})
 }
@@ -0,0 +1,353 @@
+// Package toolkit collects the building blocks shared by the search-style bots:
+// candidate bid generation, dominance filtering, outcome simulation and state evaluation.
+//
+// A typical bot generates candidates (pass, game.MinimalRaises, MinimalDominantBids, RandomBids),
+// removes duplicates with Dedupe, and picks the bid whose PassValue / BidValue is highest
+// under its own Evaluator.
+package toolkit
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/montplusa/auction-game/game"
+)
+
+// Pass is the bid that means dropping out of the auction.
+var Pass = [3]int{0, 0, 0}
+
+// Evaluator scores a GameState from the viewpoint of player me; larger is better.
+type Evaluator interface {
+	Evaluate(gs *game.GameState, me int) float64
+}
+
+// EvaluatorFunc adapts a plain function to Evaluator.
+type EvaluatorFunc func(gs *game.GameState, me int) float64
+
+// Evaluate calls f(gs, me).
+func (f EvaluatorFunc) Evaluate(gs *game.GameState, me int) float64 { return f(gs, me) }
+
+// Raises reports whether bid is at least maxVal on every color and above it on at least one.
+func Raises(bid, maxVal [3]int) bool {
+	return game.IsValidBid(bid, maxVal)
+}
+
+// Affordable reports whether money covers bid on every color.
+func Affordable(bid, money [3]int) bool {
+	return game.HasEnoughMoney(money, bid)
+}
+
+// RaiseTo raises maxVal one coin at a time, always on the color with the most coins left in
+// money, until the bid totals target coins or money runs out. It returns Pass if the result is
+// not a legal raise.
+func RaiseTo(maxVal, money [3]int, target int) [3]int {
+	bid := maxVal
+	for bid[0]+bid[1]+bid[2] < target {
+		best := -1
+		for c := 0; c < 3; c++ {
+			if money[c]-bid[c] > 0 && (best < 0 || money[c]-bid[c] > money[best]-bid[best]) {
+				best = c
+			}
+		}
+		if best < 0 {
+			break
+		}
+		bid[best]++
+	}
+	if !Raises(bid, maxVal) || !Affordable(bid, money) {
+		return Pass
+	}
+	return bid
+}
+
+// SpendFor returns a bid of up to total coins from money, spread so that it ranks as high as
+// possible under rules.CompareBids in a sealed-bid auction: colors in rules.ColorPriority order
+// for lexicographic and weighted comparison, and as RaiseTo from nothing for the sum.
+func SpendFor(rules game.Rules, money [3]int, total int) [3]int {
+	if rules.Compare != game.CompareLexicographic && rules.Compare != game.CompareWeighted {
+		return RaiseTo(Pass, money, total)
+	}
+	var bid [3]int
+	for _, c := range rules.ColorPriority() {
+		bid[c] = money[c]
+		if bid[c] > total {
+			bid[c] = total
+		}
+		total -= bid[c]
+	}
+	return bid
+}
+
+// BalanceExchange returns the conversion from the color with the most coins to the color with
+// the fewest that leaves the two as even as possible when rate coins buy one, or nil if no
+// conversion narrows the gap.
+func BalanceExchange(money [3]int, rate int) []game.Exchange {
+	if rate <= 0 {
+		return nil
+	}
+	hi, lo := 0, 0
+	for c := 1; c < 3; c++ {
+		if money[c] > money[hi] {
+			hi = c
+		}
+		if money[c] < money[lo] {
+			lo = c
+		}
+	}
+	amount := (money[hi] - money[lo]) / (rate + 1)
+	if amount <= 0 {
+		return nil
+	}
+	return []game.Exchange{{From: hi, To: lo, Amount: amount}}
+}
+
+// Clamp selects how MinimalDominantBidsWith treats thresholds below the current maximum.
+type Clamp int
+
+const (
+	ClampNone    Clamp = iota // 閾値をそのまま使う (最高値を下回る色があり得る)
+	ClampLeading              // 先頭から最高値と異なる最初の色まで最高値に引き上げる (Montplusa)
+	ClampAll                  // 全色を最高値に引き上げる (候補は常に合法な競り上げ)
+)
+
+// DominantOptions selects the variant of MinimalDominantBidsWith used by a bot.
+type DominantOptions struct {
+	WithoutMax bool  // 閾値に現在の最高値を含めず、最高値と等しい候補も除かない (MontplusAI Lv1)
+	Clamp      Clamp // 最高値を下回る閾値の扱い
+}
+
+// MinimalDominantBids enumerates the minimal raises of as.MaxValue that the player to move
+// (as.Turn) can make so that every other active player is outbid on at least one color they
+// cannot match. Per-color thresholds are the current maximum, the maximum plus one, and each
+// active opponent's coins plus one; thresholds are not clamped to the maximum, so candidates
+// may not be legal raises. Affordability for the mover is not checked.
+func MinimalDominantBids(gs *game.GameState, as *game.AuctionState) [][3]int {
+	return MinimalDominantBidsWith(gs, as, DominantOptions{})
+}
+
+// MinimalDominantRaises is MinimalDominantBids with every color clamped to the current maximum,
+// so every candidate is at least as.MaxValue on all colors. Each candidate appears once.
+func MinimalDominantRaises(gs *game.GameState, as *game.AuctionState) [][3]int {
+	return Dedupe(MinimalDominantBidsWith(gs, as, DominantOptions{Clamp: ClampAll}))
+}
+
+// MinimalDominantBidsWith is MinimalDominantBids with the threshold set and clamping chosen by
+// opt. Clamping can map several thresholds onto the same bid; the repetitions are kept, as the
+// bots built on it have always seen them.
+func MinimalDominantBidsWith(gs *game.GameState, as *game.AuctionState, opt DominantOptions) [][3]int {
+	me := as.Turn
+	maxVal := as.MaxValue
+
+	// thresholds per color
+	T := make([][]int, 3)
+	for c := 0; c < 3; c++ {
+		set := map[int]bool{maxVal[c] + 1: true}
+		if !opt.WithoutMax {
+			set[maxVal[c]] = true
+		}
+		for j, active := range as.Active {
+			if !active || j == me {
+				continue
+			}
+			set[gs.Moneys[j][c]+1] = true
+		}
+		for v := range set {
+			T[c] = append(T[c], v)
+		}
+		sort.Ints(T[c])
+	}
+
+	// enumerate minimal
+	cands := make([][3]int, 0)
+	for _, r := range T[0] {
+		for _, g := range T[1] {
+			for _, b := range T[2] {
+				bid := [3]int{r, g, b}
+				same := true
+				for i := 0; i < 3; i++ {
+					if opt.Clamp != ClampNone && bid[i] < maxVal[i] {
+						bid[i] = maxVal[i]
+					}
+					if bid[i] != maxVal[i] {
+						same = false
+						if opt.Clamp != ClampAll {
+							break
+						}
+					}
+				}
+				if same && !opt.WithoutMax {
+					continue
+				}
+				if IsDominantBid(bid, gs, as) {
+					cands = append(cands, bid)
+				}
+			}
+		}
+	}
+	return FilterMinimal(cands)
+}
+
+// IsDominantBid reports whether bid exceeds the coins of every other active player on at least
+// one color, i.e. no opponent can answer it with a raise.
+func IsDominantBid(bid [3]int, gs *game.GameState, as *game.AuctionState) bool {
+	me := as.Turn
+	for j, active := range as.Active {
+		if !active || j == me {
+			continue
+		}
+		exceeds := false
+		for c := 0; c < 3; c++ {
+			if bid[c] > gs.Moneys[j][c] {
+				exceeds = true
+				break
+			}
+		}
+		if !exceeds {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterMinimal removes every bid for which another candidate is no larger on all colors and
+// smaller on at least one. Equal bids do not remove each other, so repetitions are kept.
+func FilterMinimal(cands [][3]int) [][3]int {
+	res := make([][3]int, 0)
+	for i, a := range cands {
+		keep := true
+		for j, b := range cands {
+			if i == j {
+				continue
+			}
+			allLeq, anyLess := true, false
+			for c := 0; c < 3; c++ {
+				if b[c] > a[c] {
+					allLeq = false
+					break
+				}
+				if b[c] < a[c] {
+					anyLess = true
+				}
+			}
+			if allLeq && anyLess {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			res = append(res, a)
+		}
+	}
+	return res
+}
+
+// Uniform draws uniformly from [lb, ub]; it is the default sampler for RandomBids.
+func Uniform(lb, ub int) int {
+	return lb + rand.Intn(ub-lb+1)
+}
+
+// RandomBids draws up to n bids with every color in [maxVal[c], money[c]] using sample.
+// No bids are produced when money is below maxVal on some color.
+func RandomBids(maxVal, money [3]int, n int, sample func(lb, ub int) int) [][3]int {
+	bids := make([][3]int, 0, n)
+	for c := 0; c < 3; c++ {
+		if money[c] < maxVal[c] {
+			return bids
+		}
+	}
+	for len(bids) < n {
+		var rb [3]int
+		for c := 0; c < 3; c++ {
+			rb[c] = sample(maxVal[c], money[c])
+		}
+		bids = append(bids, rb)
+	}
+	return bids
+}
+
+// Dedupe returns bids without repetitions, keeping the first occurrence order.
+func Dedupe(bids [][3]int) [][3]int {
+	seen := make(map[[3]int]bool)
+	res := make([][3]int, 0, len(bids))
+	for _, b := range bids {
+		if !seen[b] {
+			seen[b] = true
+			res = append(res, b)
+		}
+	}
+	return res
+}
+
+// ApplyWin returns a copy of gs in which winner has paid bid and received jewel.
+func ApplyWin(gs *game.GameState, jewel *game.Jewel, winner int, bid [3]int) *game.GameState {
+	next := gs.Copy()
+	for c := 0; c < 3; c++ {
+		next.Moneys[winner][c] -= bid[c]
+	}
+	next.Scores[winner] += jewel.Point
+	for c := 0; c < 3; c++ {
+		next.Incomes[winner][c] += jewel.Income[c]
+	}
+	return next
+}
+
+// PassValue is the pessimistic value for me of passing now: the minimum of the unchanged state
+// and every outcome where an active opponent who can still pay as.MaxValue wins at that price.
+func PassValue(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel, me int, ev Evaluator) float64 {
+	minv := ev.Evaluate(gs.Copy(), me)
+	for i := range gs.Scores {
+		if i == me || !as.Active[i] {
+			continue
+		}
+		greater := false
+		ok := true
+		for c := 0; c < 3; c++ {
+			if as.MaxValue[c] < gs.Moneys[i][c] {
+				greater = true
+			}
+			if as.MaxValue[c] > gs.Moneys[i][c] {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		if !greater && i != as.MaxPlayer {
+			continue
+		}
+		if v := ev.Evaluate(ApplyWin(gs, jewel, i, as.MaxValue), me); v < minv {
+			minv = v
+		}
+	}
+	return minv
+}
+
+// BidValue is the pessimistic value for me of bidding bid: the minimum of winning at bid and
+// every outcome where an active opponent able to outbid it takes the jewel at that price.
+func BidValue(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel, me int, bid [3]int, ev Evaluator) float64 {
+	minv := ev.Evaluate(ApplyWin(gs, jewel, me, bid), me)
+	for i := range gs.Scores {
+		if i == me || !as.Active[i] {
+			continue
+		}
+		greater := false
+		ok := true
+		for c := 0; c < 3; c++ {
+			if bid[c] < gs.Moneys[i][c] {
+				greater = true
+			}
+			if bid[c] > gs.Moneys[i][c] {
+				ok = false
+				break
+			}
+		}
+		if !ok || !greater {
+			continue
+		}
+		if v := ev.Evaluate(ApplyWin(gs, jewel, i, bid), me); v < minv {
+			minv = v
+		}
+	}
+	return minv
+}
@@ -0,0 +1,206 @@
+package toolkit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/montplusa/auction-game/game"
+)
+
+// auction is a position used by the table tests: player turn is to move against the current
+// maximum max held by maxPlayer.
+type auction struct {
+	moneys    [][3]int
+	max       [3]int
+	maxPlayer int
+	turn      int
+	inactive  []int
+}
+
+func (a auction) states() (*game.GameState, *game.AuctionState) {
+	gs := game.NewGameState(len(a.moneys))
+	copy(gs.Moneys, a.moneys)
+	gs.Scores[0] = 2
+	as := game.NewAuctionState(a.turn, len(a.moneys))
+	as.MaxValue, as.MaxPlayer = a.max, a.maxPlayer
+	for _, i := range a.inactive {
+		as.Active[i] = false
+	}
+	return gs, as
+}
+
+var (
+	opening       = auction{[][3]int{{12, 10, 10}, {11, 10, 10}, {10, 10, 10}}, [3]int{}, -1, 0, nil}
+	raised        = auction{[][3]int{{5, 3, 4}, {2, 6, 1}, {4, 4, 4}}, [3]int{1, 0, 2}, 1, 0, nil}
+	poorRivals    = auction{[][3]int{{9, 9, 9}, {1, 5, 0}, {3, 0, 2}}, [3]int{3, 2, 2}, 2, 0, nil}
+	inactiveRival = auction{[][3]int{{1, 2, 3}, {6, 6, 6}, {4, 0, 7}, {2, 2, 2}}, [3]int{0, 1, 0}, 3, 1, []int{3}}
+)
+
+func TestRaiseTo(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxVal, money [3]int
+		target        int
+		want          [3]int
+	}{
+		{"richest color first", [3]int{1, 0, 2}, [3]int{5, 3, 4}, 5, [3]int{3, 0, 2}},
+		{"ties to the first color", [3]int{}, [3]int{2, 2, 2}, 3, [3]int{1, 1, 1}},
+		{"target already reached", [3]int{1, 1, 1}, [3]int{5, 5, 5}, 3, Pass},
+		{"money runs out", [3]int{}, [3]int{1, 0, 1}, 5, [3]int{1, 0, 1}},
+		{"maximum unaffordable", [3]int{3, 0, 0}, [3]int{2, 5, 5}, 4, Pass},
+	}
+	for _, tt := range tests {
+		if got := RaiseTo(tt.maxVal, tt.money, tt.target); got != tt.want {
+			t.Errorf("%s: RaiseTo(%v, %v, %d) = %v, want %v", tt.name, tt.maxVal, tt.money, tt.target, got, tt.want)
+		}
+	}
+}
+
+// The expected candidates of the bot variants are the outputs of the generateMinimalDominant
+// functions of the bots before they were moved into this package.
+func TestMinimalDominantBids(t *testing.T) {
+	montplusa := DominantOptions{Clamp: ClampLeading}
+	lv1 := DominantOptions{WithoutMax: true}
+	tests := []struct {
+		name string
+		pos  auction
+		opt  DominantOptions
+		want [][3]int
+	}{
+		{"MontplusAI Lv2/Lv3 opening", opening, DominantOptions{}, [][3]int{{0, 0, 11}, {0, 11, 0}, {12, 0, 0}}},
+		{"MontplusAI Lv2/Lv3 raised", raised, DominantOptions{}, [][3]int{{1, 0, 5}, {1, 5, 2}, {5, 0, 2}}},
+		{"MontplusAI Lv2/Lv3 poor rivals", poorRivals, DominantOptions{}, [][3]int{{2, 1, 1}}},
+		{"MontplusAI Lv2/Lv3 inactive rival", inactiveRival, DominantOptions{}, [][3]int{{0, 1, 4}, {0, 3, 0}, {2, 1, 0}}},
+		{"Montplusa opening", opening, montplusa, [][3]int{{0, 0, 11}, {0, 11, 0}, {12, 0, 0}}},
+		{"Montplusa raised", raised, montplusa, [][3]int{{1, 0, 5}, {1, 5, 2}, {5, 0, 2}}},
+		{"Montplusa poor rivals", poorRivals, montplusa, [][3]int{{3, 2, 3}, {3, 2, 3}, {3, 3, 1}, {3, 2, 3}, {3, 2, 3}, {3, 3, 1}, {4, 1, 1}}},
+		{"Montplusa inactive rival", inactiveRival, montplusa, [][3]int{{0, 1, 4}, {0, 3, 0}, {2, 1, 0}}},
+		{"MontplusAI Lv1 opening", opening, lv1, [][3]int{{1, 1, 11}, {1, 11, 1}, {12, 1, 1}}},
+		{"MontplusAI Lv1 raised", raised, lv1, [][3]int{{2, 1, 5}, {2, 5, 2}, {5, 1, 2}}},
+		{"MontplusAI Lv1 poor rivals", poorRivals, lv1, [][3]int{{2, 1, 1}}},
+		{"MontplusAI Lv1 inactive rival", inactiveRival, lv1, [][3]int{{1, 1, 4}, {1, 3, 1}, {2, 1, 1}}},
+	}
+	for _, tt := range tests {
+		gs, as := tt.pos.states()
+		if got := MinimalDominantBidsWith(gs, as, tt.opt); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	gs, as := raised.states()
+	if got, want := MinimalDominantBids(gs, as), MinimalDominantBidsWith(gs, as, DominantOptions{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("MinimalDominantBids = %v, want %v", got, want)
+	}
+}
+
+func TestMinimalDominantRaises(t *testing.T) {
+	tests := []struct {
+		name string
+		pos  auction
+		want [][3]int
+	}{
+		{"opening", opening, [][3]int{{0, 0, 11}, {0, 11, 0}, {12, 0, 0}}},
+		{"raised", raised, [][3]int{{1, 0, 5}, {1, 5, 2}, {5, 0, 2}}},
+		{"poor rivals", poorRivals, [][3]int{{3, 2, 3}, {3, 3, 2}, {4, 2, 2}}},
+	}
+	for _, tt := range tests {
+		gs, as := tt.pos.states()
+		got := MinimalDominantRaises(gs, as)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: MinimalDominantRaises = %v, want %v", tt.name, got, tt.want)
+		}
+		for _, b := range got {
+			if !Raises(b, as.MaxValue) || !IsDominantBid(b, gs, as) {
+				t.Errorf("%s: %v is not a dominant raise of %v", tt.name, b, as.MaxValue)
+			}
+		}
+	}
+}
+
+func TestIsDominantBid(t *testing.T) {
+	tests := []struct {
+		name string
+		pos  auction
+		bid  [3]int
+		want bool
+	}{
+		{"beats every rival", raised, [3]int{5, 0, 2}, true},
+		{"matchable by a rival", raised, [3]int{4, 4, 4}, false},
+		{"equal to a rival's coins", raised, [3]int{2, 6, 1}, false},
+		{"inactive rival ignored", inactiveRival, [3]int{2, 1, 0}, true},
+		{"active rival matches", inactiveRival, [3]int{0, 0, 7}, false},
+	}
+	for _, tt := range tests {
+		gs, as := tt.pos.states()
+		if got := IsDominantBid(tt.bid, gs, as); got != tt.want {
+			t.Errorf("%s: IsDominantBid(%v) = %v, want %v", tt.name, tt.bid, got, tt.want)
+		}
+	}
+}
+
+func TestFilterMinimal(t *testing.T) {
+	cands := [][3]int{{1, 2, 4}, {1, 2, 3}, {2, 1, 0}, {0, 5, 5}, {2, 1, 0}, {2, 2, 0}}
+	want := [][3]int{{1, 2, 3}, {2, 1, 0}, {0, 5, 5}, {2, 1, 0}}
+	if got := FilterMinimal(cands); !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterMinimal = %v, want %v", got, want)
+	}
+	if got := FilterMinimal(nil); len(got) != 0 {
+		t.Errorf("FilterMinimal(nil) = %v, want nothing", got)
+	}
+}
+
+// ev adds the player's score and coins and subtracts the opponents' scores.
+var ev = EvaluatorFunc(func(gs *game.GameState, me int) float64 {
+	v := float64(10*gs.Scores[me] + gs.Moneys[me][0] + gs.Moneys[me][1] + gs.Moneys[me][2])
+	for i := range gs.Scores {
+		if i != me {
+			v -= float64(5 * gs.Scores[i])
+		}
+	}
+	return v
+})
+
+func TestBidValue(t *testing.T) {
+	jewel := &game.Jewel{Point: 3, Income: [3]int{0, 1, 0}}
+	tests := []struct {
+		name string
+		pos  auction
+		bid  [3]int
+		want float64
+	}{
+		// 席 2 ({4,4,4}) が同額で取る場合が最悪
+		{"outbid by a rival", raised, [3]int{1, 1, 3}, 17},
+		// 誰も上回れないので落札した場合だけ
+		{"dominant", raised, [3]int{1, 0, 5}, 56},
+		// 降りた席 3 は同額を持っていても取れない
+		{"inactive rival", inactiveRival, [3]int{2, 1, 0}, 35},
+	}
+	for _, tt := range tests {
+		gs, as := tt.pos.states()
+		if got := BidValue(gs, as, jewel, as.Turn, tt.bid, ev); got != tt.want {
+			t.Errorf("%s: BidValue(%v) = %v, want %v", tt.name, tt.bid, got, tt.want)
+		}
+	}
+}
+
+func TestPassValue(t *testing.T) {
+	if Pass != [3]int{0, 0, 0} {
+		t.Fatalf("Pass = %v, want no coins", Pass)
+	}
+	jewel := &game.Jewel{Point: 3, Income: [3]int{0, 1, 0}}
+	tests := []struct {
+		name string
+		pos  auction
+		want float64
+	}{
+		{"opening", opening, 37},
+		{"raised", raised, 17},
+		{"poor rivals", poorRivals, 47},
+		{"inactive rival", inactiveRival, -7},
+	}
+	for _, tt := range tests {
+		gs, as := tt.pos.states()
+		if got := PassValue(gs, as, jewel, as.Turn, ev); got != tt.want {
+			t.Errorf("%s: PassValue = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// Command cfrtrain solves the two-player auction abstraction of ai/cfr with counterfactual regret
+// minimization and writes the average strategy to a file the CFR AI can load.
+//
+//	go run ./cmd/cfrtrain -iterations 2000 -out ai/cfr/strategy.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/montplusa/auction-game/ai/cfr"
+)
+
+func main() {
+	iterations := flag.Int("iterations", 2000, "number of CFR iterations")
+	out := flag.String("out", "strategy.json", "output strategy file")
+	flag.Parse()
+
+	s := cfr.Train(*iterations)
+	if err := s.Save(*out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "wrote %d information sets to %s\n", len(s.Probs), *out)
+}
@@ -0,0 +1,166 @@
+// Command scenario loads a scenario file and shows how the given AIs play its first auction,
+// action by action, optionally playing the rest of the game.
+//
+//	go run ./cmd/scenario -file puzzle.json -ais "Montplusa,決打太郎Lv3"
+//	go run ./cmd/scenario -file puzzle.json -ais "Montplusa,決打太郎Lv3" -play -seed 1
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	_ "github.com/montplusa/auction-game/ai/all"
+	"github.com/montplusa/auction-game/game"
+	"github.com/montplusa/auction-game/generator"
+)
+
+// verbose prints every action of the wrapped AI. It forwards the sealed-bid, Dutch, exchange and
+// resale decisions, so that the wrapped AI plays as it would unwrapped under any rules.
+type verbose struct {
+	game.AI
+	seat int
+}
+
+func (v verbose) SelectAction(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) [3]int {
+	bid := v.AI.SelectAction(gs, as, jewel)
+	action := "pass"
+	if game.IsLegalBid(gs, as, v.seat, bid) {
+		action = fmt.Sprintf("bid %v", bid)
+	} else if bid != [3]int{} {
+		action = fmt.Sprintf("invalid bid %v (pass)", bid)
+	}
+	v.print(action)
+	return bid
+}
+
+func (v verbose) SelectSealedBid(gs *game.GameState, jewel *game.Jewel, me int) [3]int {
+	bid := gs.SealedBid(v.AI, game.NewAuctionState(me, len(gs.Scores)), jewel)
+	action := "no bid"
+	if game.IsValidBid(bid, [3]int{}) && game.HasEnoughMoney(gs.Moneys[me], bid) {
+		action = fmt.Sprintf("sealed bid %v", bid)
+	} else if bid != [3]int{} {
+		action = fmt.Sprintf("invalid sealed bid %v (no bid)", bid)
+	}
+	v.print(action)
+	return bid
+}
+
+func (v verbose) AcceptAsk(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) bool {
+	accept := gs.AcceptsAsk(v.AI, as, jewel)
+	if accept {
+		v.print(fmt.Sprintf("accept %v", as.Asking))
+	} else {
+		v.print(fmt.Sprintf("decline %v", as.Asking))
+	}
+	return accept
+}
+
+func (v verbose) SelectExchanges(gs *game.GameState, jewel *game.Jewel, me int) []game.Exchange {
+	ex, ok := v.AI.(game.Exchanger)
+	if !ok {
+		return nil
+	}
+	exchanges := ex.SelectExchanges(gs, jewel, me)
+	for _, e := range exchanges {
+		v.print(fmt.Sprintf("exchange %d coins of color %d for %d of color %d", e.Amount*gs.Rules.Exchange, e.From, e.Amount, e.To))
+	}
+	return exchanges
+}
+
+func (v verbose) SelectSale(gs *game.GameState, me int) int {
+	s, ok := v.AI.(game.Seller)
+	if !ok {
+		return -1
+	}
+	k := s.SelectSale(gs, me)
+	if gs.CanSell(me, k) {
+		v.print(fmt.Sprintf("sell %v for %v", gs.Owned[me][k], gs.ResalePrice(gs.Owned[me][k])))
+	}
+	return k
+}
+
+func (v verbose) print(action string) {
+	fmt.Printf("  player %d (%s): %s\n", v.seat, v.GetName(), action)
+}
+
+func main() {
+	file := flag.String("file", "", "scenario JSON file")
+	aiList := flag.String("ais", "", "comma separated AI specs, one per seat")
+	play := flag.Bool("play", false, "play the rest of the game after the first auction")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed for the jewels after the listed ones")
+	rulesPath := flag.String("rules", "", "rules configuration JSON file")
+	jewels := flag.String("jewels", "", "jewel source for the jewels after the listed ones, overriding the rules file")
+	flag.Parse()
+
+	sc, err := generator.LoadScenario(*file)
+	if err != nil {
+		fail(err)
+	}
+	rules, err := game.LoadRulesOverride(*rulesPath, *jewels)
+	if err != nil {
+		fail(err)
+	}
+	src, err := generator.ForRules(rules)
+	if err != nil {
+		fail(err)
+	}
+	specs := game.SplitAISpecs(*aiList)
+	if len(specs) != sc.NumPlayers() {
+		fail(fmt.Errorf("scenario is for %d players, got %d AIs", sc.NumPlayers(), len(specs)))
+	}
+	if err := rules.ValidateFor(len(specs)); err != nil {
+		fail(err)
+	}
+	ais := make([]game.AI, len(specs))
+	for i, spec := range specs {
+		ai, err := game.NewAI(spec)
+		if err != nil {
+			fail(err)
+		}
+		ais[i] = verbose{ai, i}
+	}
+
+	if sc.Name != "" {
+		fmt.Println(sc.Name)
+	}
+	gs := sc.Start()
+	gs.Rules = rules
+	next := game.DeckJewels(sc.NewDeck(src, *seed))
+	sim := game.NewSimulator(gs, nil, gs.DrawJewel(next), ais, next)
+	fmt.Printf("phase %d round %d, jewel: %d points, income %v\n", gs.Phase, gs.Round, sim.Jewel.Point, sim.Jewel.Income)
+	if len(gs.Upcoming) > 0 {
+		fmt.Printf("upcoming: %v\n", gs.Upcoming)
+	}
+	printState(gs, ais)
+
+	sim.FinishAuction()
+	fmt.Println("after the auction:")
+	printState(sim.State, ais)
+
+	if *play {
+		for i := range ais {
+			ais[i] = ais[i].(verbose).AI
+		}
+		ranks := sim.Run()
+		fmt.Println("final:")
+		printState(sim.State, ais)
+		fmt.Printf("ranks: %v\n", ranks)
+	}
+}
+
+func printState(gs *game.GameState, ais []game.AI) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  player\tAI\tscore\tbonus\tcoins\tincome")
+	for i, ai := range ais {
+		fmt.Fprintf(w, "  %d\t%s\t%d\t%d\t%v\t%v\n", i, ai.GetName(), gs.Scores[i], gs.Bonus(i), gs.Moneys[i], gs.Incomes[i])
+	}
+	w.Flush()
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
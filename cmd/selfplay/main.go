@@ -0,0 +1,182 @@
+// Command selfplay plays games between registered AIs and exports every decision as a training
+// sample (encoded state, chosen bid, auction outcome and final rank) in JSON Lines or CSV.
+// Games are kept as game.Record values, which can be saved with -records and exported again
+// later with -from.
+//
+//	go run ./cmd/selfplay -ais "MontplusAI Lv3,決打太郎Lv3" -games 1000 -shards 4 -out data/selfplay
+//	go run ./cmd/selfplay -from games.jsonl -format csv -out data/selfplay
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	_ "github.com/montplusa/auction-game/ai/all"
+	"github.com/montplusa/auction-game/dataset"
+	"github.com/montplusa/auction-game/game"
+	"github.com/montplusa/auction-game/generator"
+)
+
+func main() {
+	aiList := flag.String("ais", "", "comma separated AI specs, one per seat")
+	games := flag.Int("games", 100, "number of games to play")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed of the first game's jewel sequence; game i uses seed+i")
+	rotate := flag.Bool("rotate", false, "rotate the seats by one every game")
+	format := flag.String("format", "jsonl", "output format: jsonl or csv")
+	out := flag.String("out", "", "output path prefix; shard files are named PREFIX-00000-of-00004.FORMAT (default: stdout)")
+	shards := flag.Int("shards", 1, "number of output files; game i goes to shard i mod shards")
+	records := flag.String("records", "", "also write the game records as JSON Lines to this file")
+	from := flag.String("from", "", "export the game records in this JSON Lines file instead of playing")
+	rulesPath := flag.String("rules", "", "rules configuration JSON file")
+	jewels := flag.String("jewels", "", "jewel source, overriding the rules file ("+strings.Join(generator.Names(), ", ")+")")
+	scenario := flag.String("scenario", "", "start every game from this scenario JSON file")
+	flag.Parse()
+
+	if *shards < 1 {
+		fail(fmt.Errorf("-shards must be positive"))
+	}
+	if *out == "" && *shards > 1 {
+		fail(fmt.Errorf("-shards needs -out"))
+	}
+
+	writers := make([]dataset.Writer, *shards)
+	for i := range writers {
+		var w io.Writer = os.Stdout
+		if *out != "" {
+			f, err := os.Create(fmt.Sprintf("%s-%05d-of-%05d.%s", *out, i, *shards, *format))
+			if err != nil {
+				fail(err)
+			}
+			defer f.Close()
+			w = f
+		}
+		bw := bufio.NewWriter(w)
+		defer bw.Flush()
+		dw, err := dataset.NewWriter(bw, *format)
+		if err != nil {
+			fail(err)
+		}
+		defer dw.Flush()
+		writers[i] = dw
+	}
+
+	var recOut *json.Encoder
+	if *records != "" {
+		f, err := os.Create(*records)
+		if err != nil {
+			fail(err)
+		}
+		defer f.Close()
+		bw := bufio.NewWriter(f)
+		defer bw.Flush()
+		recOut = json.NewEncoder(bw)
+	}
+
+	n := 0
+	export := func(rec *game.Record) {
+		if recOut != nil {
+			if err := recOut.Encode(rec); err != nil {
+				fail(err)
+			}
+		}
+		samples, err := dataset.Samples(rec, n)
+		if err != nil {
+			fail(fmt.Errorf("game %d: %v", n, err))
+		}
+		w := writers[n%*shards]
+		for _, s := range samples {
+			if err := w.Write(s); err != nil {
+				fail(err)
+			}
+		}
+		n++
+	}
+
+	if *from != "" {
+		f, err := os.Open(*from)
+		if err != nil {
+			fail(err)
+		}
+		defer f.Close()
+		dec := json.NewDecoder(bufio.NewReader(f))
+		for {
+			rec := &game.Record{}
+			if err := dec.Decode(rec); err == io.EOF {
+				break
+			} else if err != nil {
+				fail(fmt.Errorf("%s: %v", *from, err))
+			}
+			export(rec)
+		}
+	} else {
+		rules, err := game.LoadRulesOverride(*rulesPath, *jewels)
+		if err != nil {
+			fail(err)
+		}
+		source, err := generator.ForRules(rules)
+		if err != nil {
+			fail(err)
+		}
+		var sc *generator.Scenario
+		if *scenario != "" {
+			if sc, err = generator.LoadScenario(*scenario); err != nil {
+				fail(err)
+			}
+		}
+		specs := game.SplitAISpecs(*aiList)
+		N := len(specs)
+		if N < 2 {
+			fail(fmt.Errorf("-ais needs at least 2 AIs"))
+		}
+		if sc != nil && sc.NumPlayers() != N {
+			fail(fmt.Errorf("scenario is for %d players, got %d AIs", sc.NumPlayers(), N))
+		}
+		if err := rules.ValidateFor(N); err != nil {
+			fail(err)
+		}
+		infos := make([]game.AIInfo, N)
+		params := make([]game.Params, N)
+		for e, spec := range specs {
+			info, p, err := game.ParseAISpec(spec)
+			if err != nil {
+				fail(err)
+			}
+			infos[e], params[e] = info, p
+		}
+		for g := 0; g < *games; g++ {
+			ais := make([]game.AI, N)
+			players := make([]string, N)
+			for e := range specs {
+				seat := e
+				if *rotate {
+					seat = (e + g) % N
+				}
+				ais[seat] = infos[e].Instantiate(params[e])
+				players[seat] = infos[e].Spec(params[e])
+			}
+			s := *seed + int64(g)
+			gs := game.StartGame(N, rules)
+			deck := generator.NewDeckFrom(source, s, game.JewelsPerGame(N))
+			if sc != nil {
+				gs = sc.Start()
+				gs.Rules = rules
+				deck = sc.NewDeck(source, s)
+			}
+			rec := game.RecordGameFrom(gs, ais, game.DeckJewels(deck))
+			rec.Seed, rec.Players = s, players
+			export(rec)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "exported %d games\n", n)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
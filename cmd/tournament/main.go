@@ -0,0 +1,123 @@
+// Command tournament plays headless games between registered AIs and prints per-AI standings.
+//
+//	go run ./cmd/tournament -ais "MontplusAI Lv3,決打太郎Lv3,RandomAI" -games 20 -duplicate
+//	go run ./cmd/tournament -ais "MontplusAI Lv3{alpha=1.5},MontplusAI Lv3" -duplicate -games 500 -sprt
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	_ "github.com/montplusa/auction-game/ai/all"
+	"github.com/montplusa/auction-game/game"
+	"github.com/montplusa/auction-game/generator"
+	"github.com/montplusa/auction-game/tournament"
+)
+
+func main() {
+	aiList := flag.String("ais", "", "comma separated AI specs, one per seat (e.g. \"MontplusAI Lv3{alpha=1.5},RandomAI\")")
+	games := flag.Int("games", 10, "number of seeded jewel sequences to play (maximum with -sprt)")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed of the first jewel sequence")
+	duplicate := flag.Bool("duplicate", false, "replay every jewel sequence under all seat rotations")
+	compare := flag.String("compare", "", "two entrants \"A,B\" to compare on identical deals (default: the first two seats)")
+	sprt := flag.Bool("sprt", false, "stop as soon as an SPRT decides whether A is better or worse than B")
+	delta := flag.Float64("sprt-delta", tournament.DefaultSPRT.Delta, "SPRT: paired win rate tested is 0.5±delta")
+	alpha := flag.Float64("sprt-alpha", tournament.DefaultSPRT.Alpha, "SPRT: false positive rate")
+	beta := flag.Float64("sprt-beta", tournament.DefaultSPRT.Beta, "SPRT: false negative rate")
+	rulesPath := flag.String("rules", "", "rules configuration JSON file")
+	jewels := flag.String("jewels", "", "jewel source, overriding the rules file ("+strings.Join(generator.Names(), ", ")+")")
+	scenario := flag.String("scenario", "", "start every game from this scenario JSON file")
+	flag.Parse()
+
+	rules, err := game.LoadRulesOverride(*rulesPath, *jewels)
+	if err != nil {
+		fail(err)
+	}
+
+	cfg := tournament.Config{
+		AIs:       game.SplitAISpecs(*aiList),
+		Games:     *games,
+		Seed:      *seed,
+		Duplicate: *duplicate,
+		Rules:     rules,
+	}
+	if *scenario != "" {
+		if cfg.Scenario, err = generator.LoadScenario(*scenario); err != nil {
+			fail(err)
+		}
+	}
+	a, b := 0, 1
+	if *compare != "" {
+		pair := game.SplitAISpecs(*compare)
+		if len(pair) != 2 {
+			fail(fmt.Errorf("-compare needs exactly two names, got %q", *compare))
+		}
+		a, b = indexOf(cfg.AIs, pair[0]), indexOf(cfg.AIs, pair[1])
+		if a < 0 || b < 0 || a == b {
+			fail(fmt.Errorf("-compare names must be two different entrants of -ais"))
+		}
+	}
+
+	var (
+		rep *tournament.Report
+		res tournament.SPRTResult
+	)
+	if *sprt {
+		rep, res, err = tournament.RunSPRT(cfg, a, b, tournament.SPRT{Delta: *delta, Alpha: *alpha, Beta: *beta})
+	} else {
+		rep, err = tournament.Run(cfg)
+	}
+	if err != nil {
+		fail(err)
+	}
+
+	fmt.Printf("seed=%d deals=%d rotations=%d\n", cfg.Seed, len(rep.Deals), rep.Rotations)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "AI\tgames\tmean rank\tmean score\tscore diff\twin rate")
+	for _, st := range rep.Standings {
+		fmt.Fprintf(w, "%s\t%d\t%.3f ±%.3f\t%.2f ±%.2f\t%+.2f ±%.2f\t%.1f%%\n",
+			st.Name, st.Games, st.MeanRank, st.RankCI, st.MeanScore, st.ScoreCI,
+			st.MeanScoreDiff, st.ScoreDiffCI, 100*st.WinRate)
+	}
+	w.Flush()
+	if len(rep.Teams) > 0 {
+		fmt.Println()
+		fmt.Fprintln(w, "team\tgames\tmean team score\twin rate")
+		for _, ts := range rep.Teams {
+			fmt.Fprintf(w, "%s\t%d\t%.2f ±%.2f\t%.1f%%\n", ts.Name, ts.Games, ts.MeanScore, ts.ScoreCI, 100*ts.WinRate)
+		}
+		w.Flush()
+	}
+
+	if *compare == "" && !*sprt {
+		return
+	}
+	p := rep.Compare(a, b)
+	fmt.Printf("\n%s vs %s over %d deals: %d-%d-%d (W-L-D)\n", p.A, p.B, p.Deals, p.Wins, p.Losses, p.Draws)
+	fmt.Printf("rank advantage %+.3f ±%.3f, score advantage %+.2f ±%.2f (95%%)", p.RankDiff, p.RankDiffCI, p.ScoreDiff, p.ScoreDiffCI)
+	if p.Significant() {
+		fmt.Print(" significant")
+	}
+	fmt.Println()
+	if *sprt {
+		fmt.Printf("SPRT: LLR %.3f in [%.3f, %.3f] -> %s\n", res.LLR, res.Lower, res.Upper, res.Verdict)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
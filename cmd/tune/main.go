@@ -0,0 +1,79 @@
+// Command tune optimizes the parameters of a registered AI by self-play against a fixed opponent
+// pool and writes the best parameter set with its evaluation statistics as JSON.
+//
+//	go run ./cmd/tune -ai "MontplusAI Lv3" -opponents "決打太郎Lv3,Montplusa" -iterations 50 -out best.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	_ "github.com/montplusa/auction-game/ai/all"
+	"github.com/montplusa/auction-game/game"
+	"github.com/montplusa/auction-game/generator"
+	"github.com/montplusa/auction-game/tune"
+)
+
+func main() {
+	ai := flag.String("ai", "", "spec of the AI to tune; given parameters are the starting point")
+	params := flag.String("params", "", "comma separated parameter names to tune (default: all)")
+	opponents := flag.String("opponents", "", "comma separated opponent specs")
+	iterations := flag.Int("iterations", 30, "number of SPSA iterations")
+	deals := flag.Int("deals", 10, "deals per evaluation (every seat rotation is played)")
+	final := flag.Int("final", 50, "deals for the final held-out evaluation")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed for deals and perturbations")
+	objective := flag.String("objective", "score", "quantity to optimize: score (score relative to the table mean) or rank (mean rank)")
+	a := flag.Float64("a", 0.05, "SPSA step size in normalized parameter space")
+	c := flag.Float64("c", 0.05, "SPSA perturbation size in normalized parameter space")
+	out := flag.String("out", "", "write the result JSON to this file (default: stdout)")
+	rulesPath := flag.String("rules", "", "rules configuration JSON file")
+	jewels := flag.String("jewels", "", "jewel source, overriding the rules file ("+strings.Join(generator.Names(), ", ")+")")
+	flag.Parse()
+
+	rules, err := game.LoadRulesOverride(*rulesPath, *jewels)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	cfg := tune.Config{
+		AI:         *ai,
+		Opponents:  game.SplitAISpecs(*opponents),
+		Iterations: *iterations,
+		Deals:      *deals,
+		Final:      *final,
+		Seed:       *seed,
+		Objective:  *objective,
+		A:          *a,
+		C:          *c,
+		Rules:      rules,
+		Progress: func(iter int, spec string, lossPlus, lossMinus float64) {
+			fmt.Fprintf(os.Stderr, "iter %d: loss %+.4f / %+.4f -> %s\n", iter, lossPlus, lossMinus, spec)
+		},
+	}
+	for _, p := range strings.Split(*params, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			cfg.Tune = append(cfg.Tune, p)
+		}
+	}
+	res, err := tune.Run(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	data, _ := json.MarshalIndent(res, "", "  ")
+	if *out == "" {
+		fmt.Println(string(data))
+		return
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "best: %s (loss %+.4f, mean rank %.3f ±%.3f)\n", res.Best.Spec, res.Best.Loss, res.Best.MeanRank, res.Best.RankCI)
+}
@@ -0,0 +1,144 @@
+// Package dataset turns game records into training samples, one per decision, and writes them
+// as JSON Lines or CSV.
+package dataset
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/montplusa/auction-game/ai/nn"
+	"github.com/montplusa/auction-game/game"
+)
+
+// Sample is one decision of one player.
+type Sample struct {
+	Game    int       `json:"game"`    // 対局番号
+	Seed    int64     `json:"seed"`    // 対局の宝石列の seed
+	Auction int       `json:"auction"` // 対局内のオークション番号 (0 始まり)
+	Phase   int       `json:"phase"`
+	Round   int       `json:"round"`
+	Player  int       `json:"player"` // 手番の席
+	AI      string    `json:"ai"`     // 手番の AI (Record.Players の値)
+	State   []float64 `json:"state"`  // nn.EncodeState による盤面の特徴量
+	Bid     [3]int    `json:"bid"`    // 選んだ提示額
+	Valid   bool      `json:"valid"`  // false なら降りた扱い
+	Winner  int       `json:"winner"` // オークションの落札者 (落札なしは -1)
+	Price   [3]int    `json:"price"`  // 落札額
+	Won     bool      `json:"won"`    // 手番の席が落札したか
+	Rank    int       `json:"rank"`   // 手番の席の最終順位
+	Players int       `json:"players"`
+}
+
+// Samples replays rec and returns a sample for every recorded action, numbered as game g.
+// Records of Dutch auctions are rejected: their decisions are taken at an asking price that the
+// state encoding does not describe.
+func Samples(rec *game.Record, g int) ([]Sample, error) {
+	if rec.Rules.Auction == game.AuctionDutch {
+		return nil, fmt.Errorf("dataset: dutch auctions are not supported")
+	}
+	// Replay は記録された行動を順に訪れるので、k 番目の行動が属するオークションは記録の区切りで決まる
+	// (入札のなかったオークションも番号を 1 つ使う)
+	var owners []int
+	for a := range rec.Auctions {
+		for range rec.Auctions[a].Bids {
+			owners = append(owners, a)
+		}
+	}
+	var res []Sample
+	_, err := rec.Replay(func(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel, b game.BidRecord) {
+		a := owners[len(res)]
+		ar := &rec.Auctions[a]
+		res = append(res, Sample{
+			Game:    g,
+			Seed:    rec.Seed,
+			Auction: a,
+			Phase:   gs.Phase,
+			Round:   gs.Round,
+			Player:  b.Player,
+			AI:      rec.Players[b.Player],
+			State:   nn.EncodeState(gs, as, jewel, b.Player),
+			Bid:     b.Bid,
+			Valid:   b.Valid,
+			Winner:  ar.Winner,
+			Price:   ar.Price,
+			Won:     ar.Winner == b.Player,
+			Rank:    rec.Ranks[b.Player],
+			Players: len(rec.Players),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Writer writes samples in one output format.
+type Writer interface {
+	Write(s Sample) error
+	// Flush writes any buffered data to the underlying writer.
+	Flush() error
+}
+
+// Formats lists the names accepted by NewWriter.
+var Formats = []string{"jsonl", "csv"}
+
+// NewWriter returns a Writer producing format ("jsonl" or "csv") on w.
+// The CSV writer emits a header row before the first sample.
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case "jsonl":
+		return &jsonlWriter{enc: json.NewEncoder(w)}, nil
+	case "csv":
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("dataset: unknown format %q", format)
+}
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func (w *jsonlWriter) Write(s Sample) error { return w.enc.Encode(s) }
+
+func (w *jsonlWriter) Flush() error { return nil }
+
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+// csvHeader returns the column names; the state vector is spread over columns s0, s1, ...
+func csvHeader() []string {
+	h := []string{"game", "seed", "auction", "phase", "round", "player", "ai",
+		"bid_r", "bid_g", "bid_b", "valid", "winner", "price_r", "price_g", "price_b", "won", "rank", "players"}
+	for i := 0; i < nn.StateSize; i++ {
+		h = append(h, "s"+strconv.Itoa(i))
+	}
+	return h
+}
+
+func (w *csvWriter) Write(s Sample) error {
+	if !w.header {
+		if err := w.w.Write(csvHeader()); err != nil {
+			return err
+		}
+		w.header = true
+	}
+	itoa := strconv.Itoa
+	row := []string{itoa(s.Game), strconv.FormatInt(s.Seed, 10), itoa(s.Auction), itoa(s.Phase), itoa(s.Round),
+		itoa(s.Player), s.AI, itoa(s.Bid[0]), itoa(s.Bid[1]), itoa(s.Bid[2]), strconv.FormatBool(s.Valid),
+		itoa(s.Winner), itoa(s.Price[0]), itoa(s.Price[1]), itoa(s.Price[2]), strconv.FormatBool(s.Won),
+		itoa(s.Rank), itoa(s.Players)}
+	for _, v := range s.State {
+		row = append(row, strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	return w.w.Write(row)
+}
+
+func (w *csvWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
@@ -0,0 +1,72 @@
+package dataset
+
+import (
+	"testing"
+
+	"github.com/montplusa/auction-game/game"
+	"github.com/montplusa/auction-game/generator"
+)
+
+// taker bids one coin of its richest color, buys every Dutch auction at the first price it is
+// offered, and changes red coins into blue ones from the second phase on.
+type taker struct{}
+
+func (taker) GetName() string { return "taker" }
+
+func (taker) SelectAction(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) [3]int {
+	money := gs.Moneys[as.Turn]
+	best := 0
+	for c := 1; c < 3; c++ {
+		if money[c] > money[best] {
+			best = c
+		}
+	}
+	bid := as.MaxValue
+	bid[best]++
+	return bid
+}
+
+func (taker) AcceptAsk(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) bool {
+	return true
+}
+
+func (taker) SelectExchanges(gs *game.GameState, jewel *game.Jewel, me int) []game.Exchange {
+	if gs.Phase < 2 {
+		return nil
+	}
+	return []game.Exchange{{From: 0, To: 2, Amount: 1}}
+}
+
+func record(rules game.Rules) *game.Record {
+	gs := game.StartGame(2, rules)
+	deck := generator.NewDeck(1, game.JewelsPerGame(2))
+	return game.RecordGameFrom(gs, []game.AI{taker{}, taker{}}, game.DeckJewels(deck))
+}
+
+func TestSamplesKeyedToAuctions(t *testing.T) {
+	rec := record(game.Rules{Auction: game.AuctionSealed, Exchange: 1})
+	samples, err := Samples(rec, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bids := 0
+	for _, ar := range rec.Auctions {
+		bids += len(ar.Bids)
+	}
+	if len(samples) != bids {
+		t.Fatalf("%d samples for %d recorded actions", len(samples), bids)
+	}
+	for _, s := range samples {
+		ar := rec.Auctions[s.Auction]
+		if s.Phase != ar.Phase || s.Round != ar.Round || s.Winner != ar.Winner || s.Price != ar.Price {
+			t.Fatalf("sample of phase %d round %d is keyed to auction %d (phase %d round %d)", s.Phase, s.Round, s.Auction, ar.Phase, ar.Round)
+		}
+	}
+}
+
+func TestSamplesRejectDutch(t *testing.T) {
+	rec := record(game.Rules{Auction: game.AuctionDutch})
+	if _, err := Samples(rec, 0); err == nil {
+		t.Errorf("Samples of a Dutch auction record succeeded, want an error")
+	}
+}
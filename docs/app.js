@@ -49,6 +49,9 @@ function setupPlayerTypes() {
   // ★ WASM から AI 名を取得（配列）＋ Human を手動で追加
   const aiNames = window.getAvailableAIs();
   const options = [...aiNames, "Human"];
+  // AI のメタデータ（説明・作者・バージョン）をツールチップに使う
+  const aiInfos = {};
+  window.getAIInfos().forEach((info) => (aiInfos[info.Name] = info));
 
   for (let i = 0; i < n; i++) {
     const label = document.createElement("label");
@@ -60,6 +63,10 @@ function setupPlayerTypes() {
       const opt = document.createElement("option");
       opt.value = name;
       opt.textContent = name;
+      const info = aiInfos[name];
+      if (info) {
+        opt.title = `${info.Description} (${info.Author || "unknown"} v${info.Version})`;
+      }
       sel.appendChild(opt);
     });
     /* ★ デフォルト選択を決める ------------------------------------ */
@@ -69,8 +76,28 @@ function setupPlayerTypes() {
     } else {
       sel.value = "RandomAI"; // ← 好きな AI 名に変更可
     }
+    // パラメータ付き AI 用の入力欄 (例: alpha=1.5, beta=0.9)
+    const params = document.createElement("input");
+    params.id = `params-${i}`;
+    params.type = "text";
+    params.size = 18;
+    const updateParams = () => {
+      const info = aiInfos[sel.value];
+      const specs = (info && info.Params) || [];
+      params.style.display = specs.length ? "" : "none";
+      params.value = "";
+      // Kind 2 (ParamAI) の値は AI の指定文字列
+      const isAI = (p) => p.Kind === 2;
+      params.placeholder = specs.map((p) => `${p.Name}=${isAI(p) ? p.DefaultAI : p.Default}`).join(", ");
+      params.title = specs
+        .map((p) => `${p.Name}: ${p.Description}` + (isAI(p) ? "" : ` [${p.Min}, ${p.Max}]`))
+        .join("\n");
+    };
+    sel.addEventListener("change", updateParams);
+    updateParams();
     container.appendChild(label);
     container.appendChild(sel);
+    container.appendChild(params);
   }
 }
 
@@ -78,6 +105,39 @@ document
   .getElementById("select-players")
   .addEventListener("change", setupPlayerTypes);
 
+// 宝石の生成規則の選択肢を WASM から取得する
+function setupRules() {
+  const sel = document.getElementById("select-jewels");
+  sel.innerHTML = "";
+  window.getJewelSources().forEach((name) => {
+    const opt = document.createElement("option");
+    opt.value = name;
+    opt.textContent = name;
+    sel.appendChild(opt);
+  });
+  sel.value = "uniform";
+}
+
+// チーム戦のときだけチーム順位表を表示する
+function renderTeams(containerId, bodyId, state) {
+  const teams = state.Teams || [];
+  document.getElementById(containerId).style.display = teams.length ? "" : "none";
+  const tbody = document.getElementById(bodyId);
+  tbody.innerHTML = "";
+  [...teams]
+    .sort((a, b) => a.Rank - b.Rank)
+    .forEach((t) => {
+      const tr = document.createElement("tr");
+      const members = t.Members.map((i) => `${i}: ${state.Players[i].Name}`).join(", ");
+      [t.Rank, t.Team, members, t.Score, t.Coins].forEach((val) => {
+        const td = document.createElement("td");
+        td.textContent = val;
+        tr.appendChild(td);
+      });
+      tbody.appendChild(tr);
+    });
+}
+
 function showFinalResults(state) {
   const overlay = document.getElementById("result-overlay");
   const tbody = document.querySelector("#final-table tbody");
@@ -85,7 +145,9 @@ function showFinalResults(state) {
 
   // プレイヤーを得点＋コイン合計でソート
   const sorted = [...state.Players].sort((a, b) => {
-    if (b.Score !== a.Score) return b.Score - a.Score;
+    const totalA = a.Score + (a.Bonus || 0);
+    const totalB = b.Score + (b.Bonus || 0);
+    if (totalB !== totalA) return totalB - totalA;
     const sumB = b.Moneys.reduce((s, v) => s + v, 0);
     const sumA = a.Moneys.reduce((s, v) => s + v, 0);
     return sumB - sumA;
@@ -95,13 +157,15 @@ function showFinalResults(state) {
     const tr = document.createElement("tr");
     const coinStr = p.Moneys.join(",");
     const incomeStr = p.Income.join(",");
-    [i + 1, p.Index, p.Name, p.Score, coinStr, incomeStr].forEach((val) => {
+    const score = p.Bonus ? `${p.Score} (+${p.Bonus})` : p.Score;
+    [i + 1, p.Index, p.Name, score, coinStr, incomeStr].forEach((val) => {
       const td = document.createElement("td");
       td.textContent = val;
       tr.appendChild(td);
     });
     tbody.appendChild(tr);
   });
+  renderTeams("final-team-container", "final-team-body", state);
   drawScoreChart();
   drawIncomeChart();
   overlay.style.display = "flex";
@@ -172,6 +236,15 @@ function updateUI(state) {
   document.getElementById("jewel-point").textContent = state.Jewel.Point;
   document.getElementById("jewel-income").textContent =
     state.Jewel.Income.join(",");
+  document.getElementById("jewel-category-row").style.display = state.Jewel.Category ? "" : "none";
+  document.getElementById("jewel-category").textContent = state.Jewel.Category || "";
+
+  // 先読みルールで公開されている次の宝石
+  const upcoming = state.Upcoming || [];
+  document.getElementById("upcoming-container").style.display = upcoming.length ? "" : "none";
+  document.getElementById("upcoming-list").innerHTML = upcoming
+    .map((j) => `<li>得点 ${j.Point} / 収入 ${j.Income.join(",")}${j.Category ? ` / 種類 ${j.Category}` : ""}</li>`)
+    .join("");
 
   // Auction info
   document.getElementById("highest-player").textContent =
@@ -179,6 +252,10 @@ function updateUI(state) {
   document.getElementById("highest-bid").innerHTML = makeBadges(
     state.Auction.MaxValue
   );
+  const asking = state.Auction.Asking || [0, 0, 0];
+  const hasAsking = asking.some((v) => v > 0);
+  document.getElementById("asking-container").style.display = hasAsking ? "" : "none";
+  document.getElementById("asking-price").innerHTML = makeBadges(asking);
 
   // Players table 用に、各コインの最大値を計算
   const maxMoney = state.Players.reduce(
@@ -226,7 +303,10 @@ function updateUI(state) {
 
     // 列データをバー化
     // 基本情報（#, 名前, 順位, 得点）
-    [p.Index, p.Name, p.Rank, p.Score].forEach((val) => {
+    const name = (state.Teams || []).length ? `${p.Name} (チーム ${p.Team})` : p.Name;
+    // セット収集ボーナスがあれば得点に添える
+    const score = p.Bonus ? `${p.Score} (+${p.Bonus})` : p.Score;
+    [p.Index, name, p.Rank, score].forEach((val) => {
       const td = document.createElement("td");
       td.textContent = val;
       tr.appendChild(td);
@@ -282,6 +362,7 @@ function updateUI(state) {
   if (hi) hi.classList.add("current-turn");
 
   updateScoreLine(state);
+  renderTeams("team-container", "team-body", state);
 
   // Human controls
   const humanControls = document.getElementById("human-controls");
@@ -375,9 +456,24 @@ function bindStart() {
     const n = parseInt(document.getElementById("select-players").value, 10);
     const types = [];
     for (let i = 0; i < n; i++) {
-      types.push(document.getElementById(`type-${i}`).value);
+      const name = document.getElementById(`type-${i}`).value;
+      const params = document.getElementById(`params-${i}`).value.trim();
+      types.push(params ? `${name}{${params}}` : name);
+    }
+    const rules = {
+      jewels: document.getElementById("select-jewels").value,
+      preview: parseInt(document.getElementById("input-preview").value, 10) || 0,
+      auction: document.getElementById("select-auction").value,
+    };
+    const teams = document.getElementById("input-teams").value.trim();
+    if (teams) {
+      rules.teams = teams.split(",").map((t) => parseInt(t, 10));
+    }
+    const err = window.initGame(n, JSON.stringify(types), JSON.stringify(rules));
+    if (err) {
+      alert(err);
+      return;
     }
-    window.initGame(n, JSON.stringify(types));
     document.getElementById("config").style.display = "none";
     document.getElementById("visualizer").style.display = "block";
 
@@ -650,6 +746,7 @@ function updateScoreLine(state) {
 (async () => {
   await loadWasm();
   setupPlayerTypes();
+  setupRules();
   bindStart();
   bindControls();
   bindHumanControls();
@@ -0,0 +1,238 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParamKind は AI パラメータの型
+type ParamKind int
+
+const (
+	ParamFloat ParamKind = iota // 実数
+	ParamInt                    // 整数
+	ParamAI                     // 登録済み AI の指定文字列 (ParseAISpec の形式、ロールアウト方策など)
+)
+
+// ParamSpec describes one tunable parameter of a registered AI.
+type ParamSpec struct {
+	Name        string
+	Kind        ParamKind
+	Default     float64
+	DefaultAI   string  // Kind が ParamAI のときの既定値
+	Min, Max    float64 // 許容範囲 (両端を含む、ParamAI では使わない)
+	Description string
+}
+
+// defaultValue returns the default of ps as stored in Params.
+func (ps ParamSpec) defaultValue() interface{} {
+	if ps.Kind == ParamAI {
+		return ps.DefaultAI
+	}
+	return ps.Default
+}
+
+// Params maps parameter names to values: float64 for numeric parameters, with integer
+// parameters stored as whole floats, and the spec string for ParamAI parameters.
+type Params map[string]interface{}
+
+// Float returns the value of numeric parameter name.
+func (p Params) Float(name string) float64 {
+	v, _ := toFloat(p[name])
+	return v
+}
+
+// Int returns the value of integer parameter name.
+func (p Params) Int(name string) int { return int(math.Round(p.Float(name))) }
+
+// AI returns the spec of ParamAI parameter name, for NewAI.
+func (p Params) AI(name string) string {
+	s, _ := p[name].(string)
+	return s
+}
+
+// toFloat converts a numeric parameter value to float64.
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// WithDefaults returns a copy of p in which every parameter of specs missing from p has its
+// default, so that constructors called with partial or empty Params still get usable values.
+func (p Params) WithDefaults(specs []ParamSpec) Params {
+	res := Params{}
+	for _, ps := range specs {
+		res[ps.Name] = ps.defaultValue()
+	}
+	for name, v := range p {
+		res[name] = v
+	}
+	return res
+}
+
+// ParamCtor はパラメータを受け取って AI インスタンスを返す関数型
+type ParamCtor func(p Params) AI
+
+// ValidateParams checks p against info.Params and returns a copy with defaults filled in.
+// The spec of a ParamAI parameter must name a registered AI with valid parameters.
+func (info AIInfo) ValidateParams(p Params) (Params, error) {
+	res := Params{}.WithDefaults(info.Params)
+	for name, v := range p {
+		ps, ok := info.param(name)
+		if !ok {
+			return nil, fmt.Errorf("game: AI %q has no parameter %q", info.Name, name)
+		}
+		if ps.Kind == ParamAI {
+			spec, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("game: parameter %q of AI %q must be an AI spec, got %v", name, info.Name, v)
+			}
+			if _, _, err := ParseAISpec(spec); err != nil {
+				return nil, fmt.Errorf("game: parameter %q of AI %q: %v", name, info.Name, err)
+			}
+			res[name] = spec
+			continue
+		}
+		f, ok := toFloat(v)
+		if !ok {
+			return nil, fmt.Errorf("game: parameter %q of AI %q must be a number, got %v", name, info.Name, v)
+		}
+		if ps.Kind == ParamInt && f != math.Trunc(f) {
+			return nil, fmt.Errorf("game: parameter %q of AI %q must be an integer, got %v", name, info.Name, f)
+		}
+		if f < ps.Min || f > ps.Max || math.IsNaN(f) {
+			return nil, fmt.Errorf("game: parameter %q of AI %q must be in [%v, %v], got %v", name, info.Name, ps.Min, ps.Max, f)
+		}
+		res[name] = f
+	}
+	return res, nil
+}
+
+func (info AIInfo) param(name string) (ParamSpec, bool) {
+	for _, ps := range info.Params {
+		if ps.Name == name {
+			return ps, true
+		}
+	}
+	return ParamSpec{}, false
+}
+
+// Instantiate creates an AI with parameters already checked by ValidateParams. Missing
+// parameters take their defaults.
+func (info AIInfo) Instantiate(p Params) AI {
+	if info.NewWithParams != nil {
+		return info.NewWithParams(p.WithDefaults(info.Params))
+	}
+	return info.New()
+}
+
+// Spec returns the canonical spec string for info with p, listing only non-default values,
+// e.g. "MontplusAI Lv3{alpha=1.5}". ParseAISpec(info.Spec(p)) yields the same AI and parameters.
+func (info AIInfo) Spec(p Params) string {
+	var parts []string
+	for _, ps := range info.Params {
+		if _, ok := p[ps.Name]; !ok {
+			continue
+		}
+		if ps.Kind == ParamAI {
+			if spec := p.AI(ps.Name); spec != ps.DefaultAI {
+				parts = append(parts, ps.Name+"="+spec)
+			}
+			continue
+		}
+		// int と float64 のどちらで渡されても数値として既定値と比べる
+		if f := p.Float(ps.Name); f != ps.Default {
+			parts = append(parts, ps.Name+"="+strconv.FormatFloat(f, 'g', -1, 64))
+		}
+	}
+	if len(parts) == 0 {
+		return info.Name
+	}
+	return info.Name + "{" + strings.Join(parts, ",") + "}"
+}
+
+// ParseAISpec resolves a spec of the form "name" or "name{key=value,...}" where name is a registered
+// ID or display name. The value of a ParamAI parameter is itself a spec and may have its own
+// parameter list, e.g. "MCTS{rollout=MontplusAI Lv3{alpha=1.5},c=1}". The returned parameters are
+// validated and have defaults filled in. An AI registered with a non-nil Err cannot be resolved.
+func ParseAISpec(spec string) (AIInfo, Params, error) {
+	spec = strings.TrimSpace(spec)
+	name, body := spec, ""
+	if i := strings.IndexByte(spec, '{'); i >= 0 {
+		if !strings.HasSuffix(spec, "}") {
+			return AIInfo{}, nil, fmt.Errorf("game: unterminated parameter list in %q", spec)
+		}
+		name, body = strings.TrimSpace(spec[:i]), spec[i+1:len(spec)-1]
+	}
+	info, ok := LookupAI(name)
+	if !ok {
+		return AIInfo{}, nil, fmt.Errorf("game: unknown AI %q", name)
+	}
+	if info.Err != nil {
+		return AIInfo{}, nil, fmt.Errorf("game: AI %q is unavailable: %v", name, info.Err)
+	}
+	p := Params{}
+	for _, kv := range SplitAISpecs(body) {
+		eq := strings.IndexByte(kv, '=')
+		if eq < 0 {
+			return AIInfo{}, nil, fmt.Errorf("game: parameter %q in %q is not key=value", kv, spec)
+		}
+		key, value := strings.TrimSpace(kv[:eq]), strings.TrimSpace(kv[eq+1:])
+		if ps, ok := info.param(key); ok && ps.Kind == ParamAI {
+			p[key] = value
+			continue
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return AIInfo{}, nil, fmt.Errorf("game: parameter %q in %q: %v", key, spec, err)
+		}
+		p[key] = v
+	}
+	p, err := info.ValidateParams(p)
+	if err != nil {
+		return AIInfo{}, nil, err
+	}
+	return info, p, nil
+}
+
+// NewAI creates the AI described by spec (see ParseAISpec).
+func NewAI(spec string) (AI, error) {
+	info, p, err := ParseAISpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	return info.Instantiate(p), nil
+}
+
+// SplitAISpecs splits a comma separated list of specs, ignoring commas inside parameter lists.
+func SplitAISpecs(s string) []string {
+	var res []string
+	depth, start := 0, 0
+	add := func(f string) {
+		if f = strings.TrimSpace(f); f != "" {
+			res = append(res, f)
+		}
+	}
+	for i, r := range s {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				add(s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	add(s[start:])
+	return res
+}
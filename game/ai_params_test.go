@@ -0,0 +1,101 @@
+package game
+
+import "testing"
+
+func TestWithDefaults(t *testing.T) {
+	specs := []ParamSpec{
+		{Name: "alpha", Kind: ParamFloat, Default: 1.2, Min: 0, Max: 10},
+		{Name: "n", Kind: ParamInt, Default: 100, Min: 0, Max: 1000},
+	}
+	p := Params{"n": 7}
+	got := p.WithDefaults(specs)
+	if got.Float("alpha") != 1.2 || got.Int("n") != 7 {
+		t.Errorf("WithDefaults = %v, want alpha=1.2 n=7", got)
+	}
+	if _, ok := p["alpha"]; ok {
+		t.Errorf("WithDefaults modified its receiver: %v", p)
+	}
+	if got := Params(nil).WithDefaults(specs); got.Int("n") != 100 {
+		t.Errorf("nil Params: n = %d, want 100", got.Int("n"))
+	}
+}
+
+func TestInstantiateFillsDefaults(t *testing.T) {
+	var got Params
+	info := AIInfo{
+		ID:            "params-test",
+		Name:          "Params Test",
+		Params:        []ParamSpec{{Name: "k", Kind: ParamInt, Default: 3, Min: 0, Max: 9}},
+		NewWithParams: func(p Params) AI { got = p; return fixedBid{} },
+	}
+	info.Instantiate(Params{})
+	if got.Int("k") != 3 {
+		t.Errorf("Instantiate passed %v, want k=3", got)
+	}
+}
+
+func TestSpecOmitsDefaults(t *testing.T) {
+	info := AIInfo{
+		Name: "Spec Test",
+		Params: []ParamSpec{
+			{Name: "alpha", Kind: ParamFloat, Default: 1.2, Min: 0, Max: 10},
+			{Name: "n", Kind: ParamInt, Default: 100, Min: 0, Max: 1000},
+			{Name: "policy", Kind: ParamAI, DefaultAI: "inner-test"},
+		},
+	}
+	tests := []struct {
+		p    Params
+		want string
+	}{
+		{Params{}, "Spec Test"},
+		// int で渡された既定値も既定値として省く
+		{Params{"alpha": 1.2, "n": 100, "policy": "inner-test"}, "Spec Test"},
+		{Params{"n": 7}, "Spec Test{n=7}"},
+		{Params{"alpha": 1.5, "n": 7.0, "policy": "Inner Test{k=3}"}, "Spec Test{alpha=1.5,n=7,policy=Inner Test{k=3}}"},
+	}
+	for _, tt := range tests {
+		if got := info.Spec(tt.p); got != tt.want {
+			t.Errorf("Spec(%v) = %q, want %q", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestParamAISpec(t *testing.T) {
+	Register(AIInfo{
+		ID:            "inner-test",
+		Name:          "Inner Test",
+		Params:        []ParamSpec{{Name: "k", Kind: ParamInt, Default: 1, Min: 0, Max: 9}},
+		NewWithParams: func(p Params) AI { return fixedBid{p.Int("k"), 0, 0} },
+	})
+	Register(AIInfo{
+		ID:   "outer-test",
+		Name: "Outer Test",
+		Params: []ParamSpec{
+			{Name: "policy", Kind: ParamAI, DefaultAI: "inner-test"},
+			{Name: "c", Kind: ParamFloat, Default: 0.5, Min: 0, Max: 2},
+		},
+		NewWithParams: func(p Params) AI { return fixedBid{} },
+	})
+
+	info, p, err := ParseAISpec("Outer Test{policy=Inner Test{k=3},c=1}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.AI("policy"); got != "Inner Test{k=3}" {
+		t.Errorf("policy = %q, want the nested spec", got)
+	}
+	if p.Float("c") != 1 {
+		t.Errorf("c = %v, want 1", p.Float("c"))
+	}
+	if got, want := info.Spec(p), "Outer Test{policy=Inner Test{k=3},c=1}"; got != want {
+		t.Errorf("Spec = %q, want %q", got, want)
+	}
+	if _, p, _ := ParseAISpec("outer-test"); p.AI("policy") != "inner-test" {
+		t.Errorf("default policy = %q, want inner-test", p.AI("policy"))
+	}
+	for _, spec := range []string{"Outer Test{policy=nobody}", "Outer Test{policy=Inner Test{k=42}}"} {
+		if _, _, err := ParseAISpec(spec); err == nil {
+			t.Errorf("ParseAISpec(%q) succeeded, want an error", spec)
+		}
+	}
+}
@@ -1,12 +1,117 @@
 package game
 
+import (
+	"fmt"
+	"sort"
+)
+
 // ctor は「AI インスタンスを返す関数」型
 type AICtor func() AI
 
-// Registry は文字列 → 生成関数 のマップ
+// Tags commonly attached to registered AIs.
+const (
+	TagBaseline     = "baseline"     // 比較の基準として使う安定版
+	TagExperimental = "experimental" // 調整中・試作
+)
+
+// AIInfo は登録された AI のメタデータと生成関数
+type AIInfo struct {
+	ID          string   // 安定した識別子 (パッケージ名など、表示名が変わっても変えない)
+	Name        string   // 表示名 (GetName と同じもの)
+	Author      string   // 作者
+	Version     string   // バージョン
+	Description string   // 戦略の概要
+	Tags        []string // TagBaseline, TagExperimental など
+	New         AICtor   // 生成関数 (省略時は NewWithParams を既定値で呼ぶ)
+
+	Params        []ParamSpec // 調整可能なパラメータ
+	NewWithParams ParamCtor   // パラメータ付き生成関数 (Params を持つ AI のみ)
+
+	Err error // 生成できない理由 (設定ファイルの読み込み失敗など)。nil でなければ ParseAISpec と NewAI はこのエラーで失敗する
+}
+
+// HasTag reports whether info carries tag.
+func (info AIInfo) HasTag(tag string) bool {
+	for _, t := range info.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// registry は ID → AIInfo、names は表示名 → ID のマップ
+var (
+	registry = map[string]AIInfo{}
+	names    = map[string]string{}
+)
+
+// Registry maps the display name of every registered AI to its constructor with default
+// parameters. Register keeps it up to date; entries added to it directly are not registered.
+//
+// Deprecated: use ListAIs to enumerate the AIs and NewAI to create one.
 var Registry = map[string]AICtor{}
 
-// RegisterAI は AI 実装側が init() で呼ぶ関数
+// Register は AI 実装側が init() で呼ぶ関数
+// ID・表示名が空、生成関数が nil、パラメータの既定値が範囲外、または ID か表示名が登録済みの AI と重複している場合は panic する。
+func Register(info AIInfo) {
+	if info.ID == "" || info.Name == "" {
+		panic(fmt.Sprintf("game: Register AI with empty ID or name (%q, %q)", info.ID, info.Name))
+	}
+	if info.New == nil && info.NewWithParams == nil {
+		panic(fmt.Sprintf("game: Register AI %q with nil constructor", info.ID))
+	}
+	if len(info.Params) > 0 && info.NewWithParams == nil {
+		panic(fmt.Sprintf("game: Register AI %q with parameters but no NewWithParams", info.ID))
+	}
+	defaults := Params{}.WithDefaults(info.Params)
+	// AI 指定の既定値は、参照先の AI がまだ登録されていないことがあるので NewAI のときに解決する
+	numeric := Params{}
+	for _, ps := range info.Params {
+		if ps.Kind != ParamAI {
+			numeric[ps.Name] = ps.Default
+		}
+	}
+	if _, err := info.ValidateParams(numeric); err != nil {
+		panic(fmt.Sprintf("game: Register AI %q with invalid default: %v", info.ID, err))
+	}
+	if info.New == nil {
+		newWithParams := info.NewWithParams
+		info.New = func() AI { return newWithParams(defaults) }
+	}
+	if _, dup := registry[info.ID]; dup {
+		panic(fmt.Sprintf("game: Register called twice for AI ID %q", info.ID))
+	}
+	if id, dup := names[info.Name]; dup {
+		panic(fmt.Sprintf("game: AI name %q of %q is already used by %q", info.Name, info.ID, id))
+	}
+	registry[info.ID] = info
+	names[info.Name] = info.ID
+	Registry[info.Name] = info.New
+}
+
+// RegisterAI は表示名をそのまま ID としてメタデータなしで登録する簡易版
 func RegisterAI(name string, ctor AICtor) {
-	Registry[name] = ctor
+	Register(AIInfo{ID: name, Name: name, New: ctor})
+}
+
+// LookupAI は ID または表示名から登録済みの AI を探す
+func LookupAI(key string) (AIInfo, bool) {
+	if info, ok := registry[key]; ok {
+		return info, true
+	}
+	if id, ok := names[key]; ok {
+		return registry[id], true
+	}
+	return AIInfo{}, false
+}
+
+// ListAIs は登録済みの AI を表示名順に返す
+func ListAIs() []AIInfo {
+	list := make([]AIInfo, 0, len(registry))
+	for _, info := range registry {
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
 }
@@ -0,0 +1,31 @@
+package game
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegistryAlias(t *testing.T) {
+	Register(AIInfo{ID: "registry-test", Name: "Registry Test", New: func() AI { return fixedBid{1, 0, 0} }})
+	ctor, ok := Registry["Registry Test"]
+	if !ok {
+		t.Fatalf("Registry has no entry for the registered AI")
+	}
+	if got := ctor().GetName(); got != "fixed" {
+		t.Errorf("Registry constructor built %q, want the registered AI", got)
+	}
+	if len(Registry) != len(ListAIs()) {
+		t.Errorf("Registry has %d entries, ListAIs %d", len(Registry), len(ListAIs()))
+	}
+}
+
+func TestUnavailableAI(t *testing.T) {
+	Register(AIInfo{ID: "broken-test", Name: "Broken Test", New: func() AI { return fixedBid{} }, Err: errors.New("missing file")})
+	if _, err := NewAI("broken-test"); err == nil || !strings.Contains(err.Error(), "missing file") {
+		t.Errorf("NewAI of an unavailable AI returned %v, want its error", err)
+	}
+	if _, ok := LookupAI("broken-test"); !ok {
+		t.Errorf("unavailable AI is not listed")
+	}
+}
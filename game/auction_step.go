@@ -1,8 +1,38 @@
 package game
 
-// StepAuction executes exactly one action in the current auction.
-// Returns true if the auction is completed with a winner, false otherwise.
+// StepAuction executes exactly one action in the current auction under g.Rules.Auction.
+// Returns true if the auction is completed, false otherwise. A sealed-bid auction completes
+// in a single step in which every active player bids; in a Dutch auction each step is one
+// player's decision at the current asking price. Under the exchange rule the first step of a
+// phase also lets every player convert coins first, and under the resale rule the step that
+// completes the last auction of a phase also lets every player sell a jewel.
 func (g *GameState) StepAuction(as *AuctionState, jewel *Jewel, ais []AI) bool {
+	return g.stepAuction(as, jewel, ais, ActionHook)
+}
+
+// stepAuction is StepAuction with an explicit hook, so that simulations can run without
+// triggering the UI callback.
+func (g *GameState) stepAuction(as *AuctionState, jewel *Jewel, ais []AI, hook func(*AuctionState, *Jewel, int, [3]int)) bool {
+	if g.exchangeDue() {
+		g.exchangeCoins(as, jewel, ais)
+	}
+	var done bool
+	switch g.Rules.Auction {
+	case AuctionSealed:
+		done = g.stepSealed(as, jewel, ais, hook)
+	case AuctionDutch:
+		done = g.stepDutch(as, jewel, ais, hook)
+	default:
+		done = g.stepOpen(as, jewel, ais, hook)
+	}
+	if done && g.resaleDue() {
+		g.sellJewels(as, ais)
+	}
+	return done
+}
+
+// stepOpen executes one action of an open (ascending) auction.
+func (g *GameState) stepOpen(as *AuctionState, jewel *Jewel, ais []AI, hook func(*AuctionState, *Jewel, int, [3]int)) bool {
 	N := len(g.Scores)
 	// Initialize internal state on first call
 	if as.Active == nil {
@@ -21,11 +51,14 @@ func (g *GameState) StepAuction(as *AuctionState, jewel *Jewel, ais []AI) bool {
 		// Player makes a bid
 		bidVal := ais[player].SelectAction(g, as, jewel)
 		// Hook for UI
-		if ActionHook != nil {
-			ActionHook(as, jewel, player, bidVal)
+		if hook != nil {
+			hook(as, jewel, player, bidVal)
 		}
 		// Validate
-		if isValidBid(bidVal, as.MaxValue) && hasEnoughMoney(g.Moneys[player], bidVal) {
+		if IsValidBid(bidVal, as.MaxValue) && HasEnoughMoney(g.Moneys[player], bidVal) {
+			if player != as.MaxPlayer {
+				as.SecondValue = as.MaxValue
+			}
 			as.MaxValue = bidVal
 			as.MaxPlayer = player
 			as.consecutivePasses = 0
@@ -48,13 +81,52 @@ func (g *GameState) StepAuction(as *AuctionState, jewel *Jewel, ais []AI) bool {
 	}
 	// Finalize auction: award jewel
 	if as.MaxPlayer >= 0 {
-		for c := 0; c < 3; c++ {
-			g.Moneys[as.MaxPlayer][c] -= as.MaxValue[c]
-		}
-		g.Scores[as.MaxPlayer] += jewel.Point
-		for c := 0; c < 3; c++ {
-			g.Incomes[as.MaxPlayer][c] += jewel.Income[c]
-		}
+		g.settle(as, jewel)
 	}
 	return true
 }
+
+// settle makes as.MaxPlayer pay for jewel under g.Rules.Payment, records the price in as.Price
+// and awards jewel, adding it to the winner's Owned list.
+func (g *GameState) settle(as *AuctionState, jewel *Jewel) {
+	winner, price := as.MaxPlayer, as.MaxValue
+	if g.Rules.Payment == PaymentSecond {
+		price = g.Rules.SecondPrice(as.MaxValue, as.SecondValue)
+	}
+	as.Price = price
+	for c := 0; c < 3; c++ {
+		g.Moneys[winner][c] -= price[c]
+	}
+	g.Scores[winner] += jewel.Point
+	for c := 0; c < 3; c++ {
+		g.Incomes[winner][c] += jewel.Income[c]
+	}
+	for len(g.Owned) < len(g.Scores) {
+		g.Owned = append(g.Owned, nil)
+	}
+	g.Owned[winner] = append(g.Owned[winner], *jewel)
+}
+
+// SecondPrice returns what the winner of bid pays when second is the highest bid of the other
+// players: second itself if bid covers it color by color (always the case in open auctions),
+// and otherwise the cheapest part of bid, taking coins off the least significant colors
+// (ColorPriority) first, that still ranks at least as high as second under CompareBids.
+// A winner without rivals (second is zero) pays the reserve price, which is zero.
+func (r Rules) SecondPrice(bid, second [3]int) [3]int {
+	if HasEnoughMoney(bid, second) {
+		return second
+	}
+	price := bid
+	order := r.ColorPriority()
+	for k := 2; k >= 0; k-- {
+		c := order[k]
+		for price[c] > 0 {
+			price[c]--
+			if price == [3]int{} || r.CompareBids(price, second) < 0 {
+				price[c]++
+				break
+			}
+		}
+	}
+	return price
+}
@@ -0,0 +1,57 @@
+package game
+
+import "testing"
+
+// fixedBid always bids the same amount, in open and sealed auctions alike.
+type fixedBid [3]int
+
+func (b fixedBid) GetName() string { return "fixed" }
+
+func (b fixedBid) SelectAction(gameState *GameState, auctionState *AuctionState, jewel *Jewel) [3]int {
+	if IsValidBid(b, auctionState.MaxValue) {
+		return b
+	}
+	return [3]int{}
+}
+
+func TestSecondPrice(t *testing.T) {
+	lex := Rules{Payment: PaymentSecond, Compare: CompareLexicographic}
+	tests := []struct {
+		name        string
+		rules       Rules
+		bid, second [3]int
+		want        [3]int
+	}{
+		{"covered rival", Rules{Payment: PaymentSecond}, [3]int{3, 2, 1}, [3]int{2, 2, 0}, [3]int{2, 2, 0}},
+		{"no rival pays the reserve", Rules{Payment: PaymentSecond}, [3]int{3, 2, 1}, [3]int{}, [3]int{}},
+		{"sum not covered", Rules{Payment: PaymentSecond}, [3]int{4, 0, 0}, [3]int{0, 3, 0}, [3]int{3, 0, 0}},
+		{"lexicographic not covered", lex, [3]int{2, 5, 5}, [3]int{1, 9, 9}, [3]int{2, 0, 0}},
+	}
+	for _, tt := range tests {
+		if got := tt.rules.SecondPrice(tt.bid, tt.second); got != tt.want {
+			t.Errorf("%s: SecondPrice(%v, %v) = %v, want %v", tt.name, tt.bid, tt.second, got, tt.want)
+		}
+	}
+}
+
+func TestSecondPriceWithoutRival(t *testing.T) {
+	for _, auction := range []string{AuctionOpen, AuctionSealed} {
+		g := NewGameState(3)
+		g.Rules = Rules{Auction: auction, Payment: PaymentSecond}
+		before := g.Moneys[1]
+		ais := []AI{fixedBid{}, fixedBid{2, 1, 0}, fixedBid{}}
+		as := NewAuctionState(0, 3)
+		jewel := &Jewel{Point: 4}
+		for !g.StepAuction(as, jewel, ais) {
+		}
+		if as.MaxPlayer != 1 {
+			t.Fatalf("%s: winner = %d, want 1", auction, as.MaxPlayer)
+		}
+		if as.Price != [3]int{} || g.Moneys[1] != before {
+			t.Errorf("%s: winner paid %v (coins %v -> %v), want the zero reserve", auction, as.Price, before, g.Moneys[1])
+		}
+		if g.Scores[1] != 4 {
+			t.Errorf("%s: winner scored %d, want 4", auction, g.Scores[1])
+		}
+	}
+}
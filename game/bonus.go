@@ -0,0 +1,127 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Scorer computes the set-collection bonus of a player from the jewels they own.
+// The bonus is added to the score for ranking (TotalScores).
+type Scorer interface {
+	// Name は RegisterScorer で登録される名前を返します。
+	Name() string
+	// Bonus は owned (落札順) を持っているプレイヤーのボーナス点を返します。
+	Bonus(owned []Jewel) int
+}
+
+// SetBonus awards Rainbow points for every set of three income jewels of different colors and
+// Triple points for every three jewels of the same Category. A jewel's income color is its
+// color with the largest income (the first one on ties); jewels without income or category do
+// not count towards the respective sets.
+type SetBonus struct {
+	ID      string
+	Rainbow int // 赤・緑・青の収入の宝石 1 個ずつの組ごとのボーナス
+	Triple  int // 同じ Category の宝石 3 個の組ごとのボーナス
+}
+
+// Name implements Scorer by returning s.ID.
+func (s SetBonus) Name() string { return s.ID }
+
+// Bonus implements Scorer: Rainbow per complete set of the three income colors plus Triple per
+// three jewels of one Category.
+func (s SetBonus) Bonus(owned []Jewel) int {
+	var colors [3]int
+	categories := map[string]int{}
+	for _, j := range owned {
+		if c := IncomeColor(j); c >= 0 {
+			colors[c]++
+		}
+		if j.Category != "" {
+			categories[j.Category]++
+		}
+	}
+	rainbows := colors[0]
+	for _, n := range colors[1:] {
+		if n < rainbows {
+			rainbows = n
+		}
+	}
+	triples := 0
+	for _, n := range categories {
+		triples += n / 3
+	}
+	return s.Rainbow*rainbows + s.Triple*triples
+}
+
+// IncomeColor returns the color with the largest income of j (the first one on ties), or -1 if
+// j has no income.
+func IncomeColor(j Jewel) int {
+	best := -1
+	for c := 0; c < 3; c++ {
+		if j.Income[c] > 0 && (best < 0 || j.Income[c] > j.Income[best]) {
+			best = c
+		}
+	}
+	return best
+}
+
+var scorers = map[string]Scorer{}
+
+// RegisterScorer registers s under s.Name(). It panics if the name is empty or taken.
+func RegisterScorer(s Scorer) {
+	name := s.Name()
+	if name == "" {
+		panic("game: scorer with empty name")
+	}
+	if _, dup := scorers[name]; dup {
+		panic("game: duplicate scorer " + name)
+	}
+	scorers[name] = s
+}
+
+// LookupScorer returns the scorer registered as name. The empty name means no bonus and
+// returns nil.
+func LookupScorer(name string) (Scorer, error) {
+	if name == "" {
+		return nil, nil
+	}
+	s, ok := scorers[name]
+	if !ok {
+		return nil, fmt.Errorf("game: unknown scorer %q (available: %v)", name, ScorerNames())
+	}
+	return s, nil
+}
+
+// ScorerNames returns the names of the registered scorers in ascending order.
+func ScorerNames() []string {
+	names := make([]string, 0, len(scorers))
+	for name := range scorers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Bonus returns the set-collection bonus of player under g.Rules.Bonus (0 without a scorer).
+func (g *GameState) Bonus(player int) int {
+	s, _ := LookupScorer(g.Rules.Bonus)
+	if s == nil || player >= len(g.Owned) {
+		return 0
+	}
+	return s.Bonus(g.Owned[player])
+}
+
+// TotalScores returns each player's score plus their set-collection bonus, the scores that
+// Ranks and TeamResults compare.
+func TotalScores(g *GameState) []int {
+	totals := append([]int(nil), g.Scores...)
+	for i := range totals {
+		totals[i] += g.Bonus(i)
+	}
+	return totals
+}
+
+func init() {
+	// 3 色の収入の組で 5 点、同じ種類 3 個で 5 点
+	RegisterScorer(SetBonus{ID: "sets", Rainbow: 5, Triple: 5})
+}
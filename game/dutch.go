@@ -0,0 +1,109 @@
+package game
+
+// DutchBidder is implemented by AIs that know how to play Dutch (descending price) auctions.
+// AIs without it are asked with SelectAction on an open auction in which another player stands
+// one coin below the asking price, and accept if they outbid it with an affordable bid.
+type DutchBidder interface {
+	// AcceptAsk reports whether player auctionState.Turn buys jewel at auctionState.Asking.
+	AcceptAsk(gameState *GameState, auctionState *AuctionState, jewel *Jewel) bool
+}
+
+// AskUnit returns the coins per price level of a Dutch auction under r (DutchMix, or {1,1,1}
+// if unset).
+func (r Rules) AskUnit() [3]int {
+	if r.DutchMix == [3]int{} {
+		return [3]int{1, 1, 1}
+	}
+	return r.DutchMix
+}
+
+// affordableLevel returns the highest price level of mix that money can pay.
+func affordableLevel(money, mix [3]int) int {
+	level := -1
+	for c := 0; c < 3; c++ {
+		if mix[c] == 0 {
+			continue
+		}
+		if l := money[c] / mix[c]; level < 0 || l < level {
+			level = l
+		}
+	}
+	return level
+}
+
+// stepDutch asks one player whether they buy at the current asking price. Players are asked in
+// seat order from the parent; after everyone has been asked the price drops by one level of
+// AskUnit. The first player to accept wins at the asking price, and the jewel stays unsold once
+// the price reaches zero. Players who cannot afford the price are skipped. Accepting is reported
+// to the hook as a bid of Asking, declining as {0,0,0}.
+func (g *GameState) stepDutch(as *AuctionState, jewel *Jewel, ais []AI, hook func(*AuctionState, *Jewel, int, [3]int)) bool {
+	N := len(g.Scores)
+	unit := g.Rules.AskUnit()
+	if as.level == 0 {
+		// 最初の呼び出し: 開始価格を決める (指定がなければ誰かが払える最高の価格)
+		as.parent = as.Turn
+		as.level = g.Rules.DutchStart
+		if as.level <= 0 {
+			for _, m := range g.Moneys {
+				if l := affordableLevel(m, unit); l > as.level {
+					as.level = l
+				}
+			}
+		}
+	}
+	for as.level > 0 {
+		for c := 0; c < 3; c++ {
+			as.Asking[c] = as.level * unit[c]
+		}
+		player := as.Turn
+		asked := HasEnoughMoney(g.Moneys[player], as.Asking)
+		if asked {
+			bid := [3]int{}
+			accept := g.AcceptsAsk(ais[player], as, jewel)
+			if accept {
+				bid = as.Asking
+			}
+			if hook != nil {
+				hook(as, jewel, player, bid)
+			}
+			if accept {
+				as.MaxPlayer, as.MaxValue = player, as.Asking
+				g.settle(as, jewel)
+				return true
+			}
+		}
+		as.Turn = (as.Turn + 1) % N
+		if as.Turn == as.parent {
+			// 全員に尋ね終えたら値下げする
+			as.level--
+		}
+		if asked {
+			return as.level == 0
+		}
+	}
+	return true
+}
+
+// AcceptsAsk asks ai, the player at as.Turn, whether it buys at as.Asking: AcceptAsk if ai is a
+// DutchBidder, and otherwise the SelectAction fallback described there.
+func (g *GameState) AcceptsAsk(ai AI, as *AuctionState, jewel *Jewel) bool {
+	if db, ok := ai.(DutchBidder); ok {
+		return db.AcceptAsk(g, as, jewel)
+	}
+	// 提示価格より 1 枚安い入札が立っている競り上げとして尋ねる
+	N := len(g.Scores)
+	open := NewAuctionState(as.Turn, N)
+	open.MaxValue = as.Asking
+	order := g.Rules.ColorPriority()
+	for k := 2; k >= 0; k-- {
+		if c := order[k]; open.MaxValue[c] > 0 {
+			open.MaxValue[c]--
+			break
+		}
+	}
+	if open.MaxValue != [3]int{} {
+		open.MaxPlayer = (as.Turn + 1) % N
+	}
+	bid := ai.SelectAction(g, open, jewel)
+	return IsValidBid(bid, open.MaxValue) && HasEnoughMoney(g.Moneys[as.Turn], bid)
+}
@@ -0,0 +1,127 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+// acceptor buys a Dutch auction when accept says so; it never bids in open auctions.
+type acceptor func(as *AuctionState) bool
+
+func (a acceptor) GetName() string { return "acceptor" }
+
+func (a acceptor) SelectAction(*GameState, *AuctionState, *Jewel) [3]int { return [3]int{} }
+
+func (a acceptor) AcceptAsk(gs *GameState, as *AuctionState, jewel *Jewel) bool { return a(as) }
+
+func never(*AuctionState) bool { return false }
+
+// ask is one question of a Dutch auction as reported to the hook.
+type ask struct {
+	player int
+	asking [3]int
+}
+
+// runDutch plays one Dutch auction from seat 0 and returns the questions asked.
+func runDutch(g *GameState, as *AuctionState, ais []AI) []ask {
+	var asks []ask
+	hook := func(as *AuctionState, _ *Jewel, player int, _ [3]int) {
+		asks = append(asks, ask{player, as.Asking})
+	}
+	for !g.stepAuction(as, &Jewel{Point: 3}, ais, hook) {
+	}
+	return asks
+}
+
+func dutchGame(rules Rules) *GameState {
+	g := NewGameState(3)
+	g.Rules = rules
+	g.Moneys = [][3]int{{5, 6, 7}, {3, 3, 3}, {9, 2, 9}}
+	return g
+}
+
+func TestDutchStartLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		want  [3]int
+	}{
+		{"highest affordable level", Rules{Auction: AuctionDutch}, [3]int{5, 5, 5}},
+		{"dutch_start", Rules{Auction: AuctionDutch, DutchStart: 2}, [3]int{2, 2, 2}},
+		{"dutch_mix", Rules{Auction: AuctionDutch, DutchMix: [3]int{2, 0, 1}}, [3]int{8, 0, 4}},
+	}
+	for _, tt := range tests {
+		g := dutchGame(tt.rules)
+		asks := runDutch(g, NewAuctionState(0, 3), []AI{acceptor(never), acceptor(never), acceptor(never)})
+		if len(asks) == 0 || asks[0].asking != tt.want {
+			t.Errorf("%s: first ask %v, want %v", tt.name, asks, tt.want)
+		}
+	}
+}
+
+func TestDutchDescentWithoutTaker(t *testing.T) {
+	g := dutchGame(Rules{Auction: AuctionDutch})
+	before := dutchGame(Rules{}).Moneys
+	as := NewAuctionState(0, 3)
+	asks := runDutch(g, as, []AI{acceptor(never), acceptor(never), acceptor(never)})
+	// 払えない席は飛ばし、全員に尋ね終えるごとに 1 段階下げる
+	want := []ask{
+		{0, [3]int{5, 5, 5}},
+		{0, [3]int{4, 4, 4}},
+		{0, [3]int{3, 3, 3}}, {1, [3]int{3, 3, 3}},
+		{0, [3]int{2, 2, 2}}, {1, [3]int{2, 2, 2}}, {2, [3]int{2, 2, 2}},
+		{0, [3]int{1, 1, 1}}, {1, [3]int{1, 1, 1}}, {2, [3]int{1, 1, 1}},
+	}
+	if !reflect.DeepEqual(asks, want) {
+		t.Errorf("asks = %v, want %v", asks, want)
+	}
+	if as.MaxPlayer != -1 || !reflect.DeepEqual(g.Moneys, before) || g.Scores[0]+g.Scores[1]+g.Scores[2] != 0 {
+		t.Errorf("unsold jewel changed the game: winner %d, moneys %v, scores %v", as.MaxPlayer, g.Moneys, g.Scores)
+	}
+}
+
+func TestDutchAcceptance(t *testing.T) {
+	g := dutchGame(Rules{Auction: AuctionDutch})
+	as := NewAuctionState(0, 3)
+	seat1 := acceptor(func(as *AuctionState) bool { return as.Turn == 1 })
+	asks := runDutch(g, as, []AI{seat1, seat1, seat1})
+	if last := asks[len(asks)-1]; last != (ask{1, [3]int{3, 3, 3}}) {
+		t.Errorf("last ask = %v, want seat 1 at [3 3 3]", last)
+	}
+	if as.MaxPlayer != 1 || as.Price != [3]int{3, 3, 3} {
+		t.Errorf("winner %d paid %v, want seat 1 paying [3 3 3]", as.MaxPlayer, as.Price)
+	}
+	if g.Moneys[1] != [3]int{} || g.Scores[1] != 3 {
+		t.Errorf("winner has coins %v and score %d, want [0 0 0] and 3", g.Moneys[1], g.Scores[1])
+	}
+}
+
+func TestDutchSelectActionFallback(t *testing.T) {
+	// DutchBidder でない AI は、提示価格より 1 枚安い入札を上回れるなら買う
+	g := dutchGame(Rules{Auction: AuctionDutch})
+	as := NewAuctionState(0, 3)
+	ais := []AI{fixedBid{4, 4, 4}, fixedBid{}, fixedBid{}}
+	runDutch(g, as, ais)
+	if as.MaxPlayer != 0 || as.Price != [3]int{4, 4, 4} {
+		t.Errorf("winner %d paid %v, want seat 0 paying [4 4 4]", as.MaxPlayer, as.Price)
+	}
+}
+
+func TestReplayVisitsDutchAskingPrice(t *testing.T) {
+	g := StartGame(2, Rules{Auction: AuctionDutch})
+	seat1 := acceptor(func(as *AuctionState) bool { return as.Turn == 1 })
+	rec := RecordGameFrom(g, []AI{seat1, seat1}, oneRedJewel)
+	visits := 0
+	_, err := rec.Replay(func(gs *GameState, as *AuctionState, jewel *Jewel, b BidRecord) {
+		visits++
+		if as.Asking == [3]int{} || as.MaxPlayer != -1 {
+			t.Fatalf("visit saw asking price %v with a bid by %d, want the Dutch auction state", as.Asking, as.MaxPlayer)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if visits == 0 {
+		t.Errorf("Replay visited no actions")
+	}
+}
@@ -0,0 +1,57 @@
+package game
+
+// Exchange is one conversion of coins between colors: the player pays Amount × Rules.Exchange
+// coins of color From and receives Amount coins of color To.
+type Exchange struct {
+	Player int `json:"player"`
+	From   int `json:"from"`   // 支払う色 (0=赤, 1=緑, 2=青)
+	To     int `json:"to"`     // 受け取る色
+	Amount int `json:"amount"` // 受け取る枚数
+}
+
+// Exchanger is implemented by AIs that convert coins when the exchange rule is on.
+// AIs without it never exchange.
+type Exchanger interface {
+	// SelectExchanges returns the conversions player me makes at the start of the phase, applied
+	// in order. Player fields are ignored; conversions that are invalid or unaffordable are skipped.
+	SelectExchanges(gameState *GameState, jewel *Jewel, me int) []Exchange
+}
+
+// CanExchange reports whether player can make e under g.Rules.
+func (g *GameState) CanExchange(player int, e Exchange) bool {
+	if g.Rules.Exchange <= 0 || e.Amount <= 0 || e.From == e.To {
+		return false
+	}
+	if e.From < 0 || e.From >= 3 || e.To < 0 || e.To >= 3 {
+		return false
+	}
+	return g.Moneys[player][e.From] >= e.Amount*g.Rules.Exchange
+}
+
+// exchangeDue reports whether the players still have to exchange coins in the current phase.
+func (g *GameState) exchangeDue() bool {
+	return g.Rules.Exchange > 0 && g.Round == 1 && g.Exchanged < g.Phase
+}
+
+// exchangeCoins asks every player, in seat order from the parent (as.Turn), for their
+// conversions and applies the valid ones. The conversions made are appended to as.Exchanges.
+func (g *GameState) exchangeCoins(as *AuctionState, jewel *Jewel, ais []AI) {
+	N := len(g.Scores)
+	for k := 0; k < N; k++ {
+		player := (as.Turn + k) % N
+		ex, ok := ais[player].(Exchanger)
+		if !ok {
+			continue
+		}
+		for _, e := range ex.SelectExchanges(g, jewel, player) {
+			e.Player = player
+			if !g.CanExchange(player, e) {
+				continue
+			}
+			g.Moneys[player][e.From] -= e.Amount * g.Rules.Exchange
+			g.Moneys[player][e.To] += e.Amount
+			as.Exchanges = append(as.Exchanges, e)
+		}
+	}
+	g.Exchanged = g.Phase
+}
@@ -0,0 +1,105 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+// trader makes fixed exchanges and a fixed sale, and never bids.
+type trader struct {
+	exchanges []Exchange
+	sale      int
+}
+
+func (t trader) GetName() string { return "trader" }
+
+func (t trader) SelectAction(*GameState, *AuctionState, *Jewel) [3]int { return [3]int{} }
+
+func (t trader) SelectExchanges(gs *GameState, jewel *Jewel, me int) []Exchange { return t.exchanges }
+
+func (t trader) SelectSale(gs *GameState, me int) int { return t.sale }
+
+func TestExchangeDue(t *testing.T) {
+	tests := []struct {
+		name            string
+		exchange        int
+		phase, round    int
+		exchanged, want bool
+	}{
+		{"first auction of the phase", 2, 3, 1, false, true},
+		{"already exchanged", 2, 3, 1, true, false},
+		{"later auction", 2, 3, 2, false, false},
+		{"rule off", 0, 3, 1, false, false},
+	}
+	for _, tt := range tests {
+		g := NewGameState(2)
+		g.Rules.Exchange = tt.exchange
+		g.Phase, g.Round = tt.phase, tt.round
+		if tt.exchanged {
+			g.Exchanged = tt.phase
+		}
+		if got := g.exchangeDue(); got != tt.want {
+			t.Errorf("%s: exchangeDue = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCanExchange(t *testing.T) {
+	g := NewGameState(2)
+	g.Rules.Exchange = 3
+	g.Moneys[0] = [3]int{6, 2, 0}
+	tests := []struct {
+		e    Exchange
+		want bool
+	}{
+		{Exchange{From: 0, To: 2, Amount: 2}, true},
+		{Exchange{From: 0, To: 2, Amount: 3}, false}, // 赤 9 枚は払えない
+		{Exchange{From: 1, To: 0, Amount: 1}, false}, // 緑 3 枚は払えない
+		{Exchange{From: 0, To: 0, Amount: 1}, false},
+		{Exchange{From: 0, To: 3, Amount: 1}, false},
+		{Exchange{From: 0, To: 1, Amount: 0}, false},
+	}
+	for _, tt := range tests {
+		if got := g.CanExchange(0, tt.e); got != tt.want {
+			t.Errorf("CanExchange(%+v) = %v, want %v", tt.e, got, tt.want)
+		}
+	}
+	g.Rules.Exchange = 0
+	if g.CanExchange(0, Exchange{From: 0, To: 2, Amount: 1}) {
+		t.Errorf("CanExchange allowed an exchange with the rule off")
+	}
+}
+
+func TestExchangeCoins(t *testing.T) {
+	g := NewGameState(3)
+	g.Rules = Rules{Exchange: 2}
+	g.Moneys = [][3]int{{4, 0, 0}, {0, 5, 0}, {1, 1, 1}}
+	ais := []AI{
+		// 2 回目の両替は 1 回目の後では払えないので飛ばされる
+		trader{exchanges: []Exchange{{From: 0, To: 1, Amount: 1}, {From: 0, To: 2, Amount: 2}, {From: 0, To: 2, Amount: 1}}},
+		trader{exchanges: []Exchange{{Player: 2, From: 1, To: 2, Amount: 2}}},
+		fixedBid{},
+	}
+	as := NewAuctionState(1, 3)
+	g.StepAuction(as, &Jewel{Point: 1}, ais)
+
+	want := [][3]int{{0, 1, 1}, {0, 1, 2}, {1, 1, 1}}
+	if !reflect.DeepEqual(g.Moneys, want) {
+		t.Errorf("moneys = %v, want %v", g.Moneys, want)
+	}
+	// 親の席 1 から順に記録され、Player は実際に両替した席になる
+	wantEx := []Exchange{{Player: 1, From: 1, To: 2, Amount: 2}, {Player: 0, From: 0, To: 1, Amount: 1}, {Player: 0, From: 0, To: 2, Amount: 1}}
+	if !reflect.DeepEqual(as.Exchanges, wantEx) {
+		t.Errorf("exchanges = %v, want %v", as.Exchanges, wantEx)
+	}
+	if g.Exchanged != g.Phase || g.exchangeDue() {
+		t.Errorf("exchange still due after the first step of the phase")
+	}
+
+	// 同じフェーズの次の手番では両替しない
+	before := append([][3]int(nil), g.Moneys...)
+	g.StepAuction(NewAuctionState(1, 3), &Jewel{Point: 1}, ais)
+	if !reflect.DeepEqual(g.Moneys, before) {
+		t.Errorf("coins exchanged twice in a phase: %v -> %v", before, g.Moneys)
+	}
+}
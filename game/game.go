@@ -2,11 +2,16 @@ package game
 
 // GameState represents the overall state of the auction game across phases and rounds.
 type GameState struct {
-	Phase   int      // 現在のフェーズ (1～10)
-	Round   int      // フェーズ内の現在ラウンド (1～3N)
-	Scores  []int    // 各プレイヤーの累計得点 (長さ N)
-	Incomes [][3]int // 各プレイヤーがフェーズ開始時に得るコイン収入 (長さ N, 各要素は [赤,緑,青])
-	Moneys  [][3]int // 各プレイヤーの現在所持コイン (長さ N, 各要素は [赤,緑,青])
+	Phase   int      `json:"phase"`   // 現在のフェーズ (1～10)
+	Round   int      `json:"round"`   // フェーズ内の現在ラウンド (1～3N)
+	Scores  []int    `json:"scores"`  // 各プレイヤーの累計得点 (長さ N)
+	Incomes [][3]int `json:"incomes"` // 各プレイヤーがフェーズ開始時に得るコイン収入 (長さ N, 各要素は [赤,緑,青])
+	Moneys  [][3]int `json:"moneys"`  // 各プレイヤーの現在所持コイン (長さ N, 各要素は [赤,緑,青])
+
+	Upcoming  []Jewel   `json:"upcoming,omitempty"`  // 先読みルールで公開されている次以降の宝石 (近い順、最大 Rules.Preview 個)
+	Exchanged int       `json:"exchanged,omitempty"` // 両替ルールで両替を済ませた最後のフェーズ
+	Owned     [][]Jewel `json:"owned,omitempty"`     // 各プレイヤーが落札して持っている宝石 (落札順)
+	Rules     Rules     `json:"-"`                   // 対局のルール設定
 }
 
 func (g *GameState) Copy() *GameState {
@@ -19,17 +24,33 @@ func (g *GameState) Copy() *GameState {
 	newg.Incomes = append(newg.Incomes, g.Incomes...)
 	newg.Moneys = make([][3]int, 0, len(g.Moneys))
 	newg.Moneys = append(newg.Moneys, g.Moneys...)
+	newg.Upcoming = append([]Jewel(nil), g.Upcoming...)
+	newg.Exchanged = g.Exchanged
+	if g.Owned != nil {
+		newg.Owned = make([][]Jewel, len(g.Owned))
+		for i, js := range g.Owned {
+			newg.Owned[i] = append([]Jewel(nil), js...)
+		}
+	}
+	newg.Rules = g.Rules
 	return &newg
 }
 
 // AuctionState holds the state for a single auction round.
 type AuctionState struct {
-	MaxPlayer         int    // 暫定最高入札者のプレイヤー番号（未入札なら -1）
-	MaxValue          [3]int // 暫定最高入札額 ([赤,緑,青], 未入札なら {0,0,0})
-	Turn              int    // 現在手番のプレイヤー番号 (0～N-1)
-	Active            []bool // 有効な入札者一覧
-	activeCount       int    // 残り有効入札者数
-	consecutivePasses int    // 連続パス数
+	MaxPlayer         int        // 暫定最高入札者のプレイヤー番号（未入札なら -1）
+	MaxValue          [3]int     // 暫定最高入札額 ([赤,緑,青], 未入札なら {0,0,0})
+	SecondValue       [3]int     // 暫定最高入札者以外の最高入札額 (2 位価格方式の支払い額の基準)
+	Price             [3]int     // 落札者が支払った額 (オークション終了後に設定)
+	Asking            [3]int     // ダッチオークションの現在の提示価格
+	Exchanges         []Exchange // フェーズ最初のオークションの開始時に行われた両替
+	Sales             []Sale     // フェーズ最後のオークションの終了時に行われた宝石の売却
+	Turn              int        // 現在手番のプレイヤー番号 (0～N-1)
+	Active            []bool     // 有効な入札者一覧
+	activeCount       int        // 残り有効入札者数
+	consecutivePasses int        // 連続パス数
+	level             int        // ダッチオークションの価格の段階 (0 なら未開始)
+	parent            int        // ダッチオークションの親 (値下げの区切り)
 }
 
 // NewAuctionState returns a freshly initialized AuctionState for a new auction.
@@ -51,8 +72,10 @@ func NewAuctionState(startTurn, numPlayers int) *AuctionState {
 
 // Jewel describes the auction item.
 type Jewel struct {
-	Point  int    // 入手した際に得られる得点 (1～10)
-	Income [3]int // 各フェーズごとに得られるコイン収入 ([赤,緑,青])
+	Point  int    `json:"point"`  // 入手した際に得られる得点 (1～10)
+	Income [3]int `json:"income"` // 各フェーズごとに得られるコイン収入 ([赤,緑,青])
+
+	Category string `json:"category,omitempty"` // 宝石の種類 (セット収集ボーナス用、空なら種類なし)
 }
 
 // AI defines the bid strategy interface.
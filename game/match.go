@@ -0,0 +1,67 @@
+package game
+
+// NumPhases is the number of phases in a full game.
+const NumPhases = 10
+
+// PlayGame plays a full game headlessly with ais seated in order and returns the final state.
+// nextJewel is called once per auction to obtain the item being sold.
+// The flow mirrors the visualizer: income at the start of every phase, 3N auctions per phase,
+// and the parent of round j is player (j-1)%N.
+func PlayGame(ais []AI, nextJewel func() *Jewel) *GameState {
+	return PlayGameFrom(StartGame(len(ais), Rules{}), ais, nextJewel)
+}
+
+// PlayGameFrom plays the rest of a game from gs, which must be at the start of an auction with
+// the income of the current phase already paid (as returned by StartGame), following gs.Rules.
+// gs is updated in place and returned.
+func PlayGameFrom(gs *GameState, ais []AI, nextJewel func() *Jewel) *GameState {
+	N := len(ais)
+	for {
+		jewel := gs.DrawJewel(nextJewel)
+		as := NewAuctionState((gs.Round-1)%N, N)
+		for !gs.StepAuction(as, jewel, ais) {
+		}
+		if !gs.NextAuction() {
+			return gs
+		}
+	}
+}
+
+// DrawJewel returns the jewel of the auction about to start: the first revealed jewel if any,
+// otherwise the next one from nextJewel. It then draws ahead so that Upcoming holds the next
+// Rules.Preview jewels, but never more than the auctions left in the game, so a deck of
+// exactly the remaining jewels is enough.
+func (g *GameState) DrawJewel(nextJewel func() *Jewel) *Jewel {
+	var jewel *Jewel
+	if len(g.Upcoming) > 0 {
+		j := g.Upcoming[0]
+		jewel = &j
+		g.Upcoming = append([]Jewel(nil), g.Upcoming[1:]...)
+	} else {
+		jewel = nextJewel()
+	}
+	want := g.Rules.Preview
+	if left := g.RemainingAuctions() - 1; want > left {
+		want = left
+	}
+	for len(g.Upcoming) < want {
+		g.Upcoming = append(g.Upcoming, *nextJewel())
+	}
+	return jewel
+}
+
+// DeckJewels returns a nextJewel function for PlayGame that hands out deck in order.
+// It panics if the game needs more jewels than deck holds.
+func DeckJewels(deck []*Jewel) func() *Jewel {
+	i := 0
+	return func() *Jewel {
+		j := deck[i]
+		i++
+		return j
+	}
+}
+
+// JewelsPerGame returns how many auctions a full game with N players holds.
+func JewelsPerGame(N int) int {
+	return NumPhases * 3 * N
+}
@@ -0,0 +1,62 @@
+package game
+
+import "math/rand"
+
+// IsLegalBid reports whether StepAuction would accept bid from player: it must raise
+// as.MaxValue (IsValidBid) and be covered by the player's coins (HasEnoughMoney).
+func IsLegalBid(g *GameState, as *AuctionState, player int, bid [3]int) bool {
+	return IsValidBid(bid, as.MaxValue) && HasEnoughMoney(g.Moneys[player], bid)
+}
+
+// MinimalRaises returns the legal bids that raise as.MaxValue by exactly one coin of one color.
+func MinimalRaises(g *GameState, as *AuctionState, player int) [][3]int {
+	res := make([][3]int, 0, 3)
+	for c := 0; c < 3; c++ {
+		bid := as.MaxValue
+		bid[c]++
+		if IsLegalBid(g, as, player, bid) {
+			res = append(res, bid)
+		}
+	}
+	return res
+}
+
+// LegalBids returns every legal bid of player that adds at most budget coins in total on top of
+// as.MaxValue, ordered by red, then green, then blue. A budget below 1 yields no bids.
+func LegalBids(g *GameState, as *AuctionState, player int, budget int) [][3]int {
+	maxVal, money := as.MaxValue, g.Moneys[player]
+	res := make([][3]int, 0)
+	for r := maxVal[0]; r <= money[0] && r-maxVal[0] <= budget; r++ {
+		for gr := maxVal[1]; gr <= money[1] && r-maxVal[0]+gr-maxVal[1] <= budget; gr++ {
+			for b := maxVal[2]; b <= money[2] && r-maxVal[0]+gr-maxVal[1]+b-maxVal[2] <= budget; b++ {
+				bid := [3]int{r, gr, b}
+				if bid != maxVal {
+					res = append(res, bid)
+				}
+			}
+		}
+	}
+	return res
+}
+
+// RandomLegalBid draws a bid uniformly from all legal bids of player, using r
+// (or the math/rand package functions when r is nil). ok is false when no legal bid exists.
+func RandomLegalBid(r *rand.Rand, g *GameState, as *AuctionState, player int) (bid [3]int, ok bool) {
+	maxVal, money := as.MaxValue, g.Moneys[player]
+	if !HasEnoughMoney(money, maxVal) || money == maxVal {
+		return [3]int{}, false
+	}
+	intn := rand.Intn
+	if r != nil {
+		intn = r.Intn
+	}
+	// 範囲 [maxVal, money] の直方体から一様に引き、maxVal と一致したら引き直す
+	for {
+		for c := 0; c < 3; c++ {
+			bid[c] = maxVal[c] + intn(money[c]-maxVal[c]+1)
+		}
+		if bid != maxVal {
+			return bid, true
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package game
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// position returns a two-player game in which player 0 holds money and is to move against a
+// standing bid of maxVal by player 1.
+func position(money, maxVal [3]int) (*GameState, *AuctionState) {
+	g := NewGameState(2)
+	g.Moneys[0] = money
+	as := NewAuctionState(0, 2)
+	as.MaxValue = maxVal
+	if maxVal != [3]int{} {
+		as.MaxPlayer = 1
+	}
+	return g, as
+}
+
+func TestIsLegalBid(t *testing.T) {
+	g, as := position([3]int{3, 2, 1}, [3]int{1, 1, 0})
+	tests := []struct {
+		bid  [3]int
+		want bool
+	}{
+		{[3]int{2, 1, 0}, true},
+		{[3]int{3, 2, 1}, true},
+		{[3]int{1, 1, 0}, false}, // 最高額と同じ
+		{[3]int{2, 0, 1}, false}, // 緑を下げている
+		{[3]int{4, 1, 0}, false}, // 赤が足りない
+		{[3]int{0, 0, 0}, false},
+	}
+	for _, tt := range tests {
+		if got := IsLegalBid(g, as, 0, tt.bid); got != tt.want {
+			t.Errorf("IsLegalBid(%v) = %v, want %v", tt.bid, got, tt.want)
+		}
+	}
+}
+
+func TestMinimalRaises(t *testing.T) {
+	tests := []struct {
+		name          string
+		money, maxVal [3]int
+		want          [][3]int
+	}{
+		{"every color", [3]int{5, 5, 5}, [3]int{1, 2, 3}, [][3]int{{2, 2, 3}, {1, 3, 3}, {1, 2, 4}}},
+		{"color exhausted", [3]int{1, 5, 5}, [3]int{1, 0, 0}, [][3]int{{1, 1, 0}, {1, 0, 1}}},
+		{"maximum unaffordable", [3]int{5, 0, 5}, [3]int{0, 1, 0}, [][3]int{}},
+	}
+	for _, tt := range tests {
+		g, as := position(tt.money, tt.maxVal)
+		if got := MinimalRaises(g, as, 0); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: MinimalRaises = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLegalBids(t *testing.T) {
+	tests := []struct {
+		name          string
+		money, maxVal [3]int
+		budget        int
+		want          [][3]int
+	}{
+		{"zero budget", [3]int{5, 5, 5}, [3]int{}, 0, [][3]int{}},
+		{"negative budget", [3]int{5, 5, 5}, [3]int{}, -1, [][3]int{}},
+		{"budget of one", [3]int{5, 5, 5}, [3]int{1, 0, 0}, 1, [][3]int{{1, 0, 1}, {1, 1, 0}, {2, 0, 0}}},
+		{"budget of two", [3]int{5, 1, 0}, [3]int{}, 2, [][3]int{{0, 1, 0}, {1, 0, 0}, {1, 1, 0}, {2, 0, 0}}},
+		{"money below budget", [3]int{1, 1, 0}, [3]int{}, 10, [][3]int{{0, 1, 0}, {1, 0, 0}, {1, 1, 0}}},
+		{"money equals maximum", [3]int{2, 1, 0}, [3]int{2, 1, 0}, 5, [][3]int{}},
+		{"maximum unaffordable", [3]int{9, 0, 9}, [3]int{0, 1, 0}, 5, [][3]int{}},
+	}
+	for _, tt := range tests {
+		g, as := position(tt.money, tt.maxVal)
+		got := LegalBids(g, as, 0, tt.budget)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: LegalBids = %v, want %v", tt.name, got, tt.want)
+		}
+		for _, bid := range got {
+			if !IsLegalBid(g, as, 0, bid) {
+				t.Errorf("%s: LegalBids returned the illegal bid %v", tt.name, bid)
+			}
+		}
+	}
+}
+
+func TestRandomLegalBid(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		money, maxVal [3]int
+	}{
+		{"money equals maximum", [3]int{2, 1, 0}, [3]int{2, 1, 0}},
+		{"maximum unaffordable", [3]int{9, 0, 9}, [3]int{0, 1, 0}},
+		{"no coins", [3]int{}, [3]int{}},
+	} {
+		g, as := position(tt.money, tt.maxVal)
+		if bid, ok := RandomLegalBid(rand.New(rand.NewSource(1)), g, as, 0); ok {
+			t.Errorf("%s: RandomLegalBid = %v, want no legal bid", tt.name, bid)
+		}
+	}
+
+	g, as := position([3]int{2, 1, 0}, [3]int{1, 0, 0})
+	want := LegalBids(g, as, 0, 3)
+	seen := map[[3]int]int{}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		bid, ok := RandomLegalBid(r, g, as, 0)
+		if !ok || !IsLegalBid(g, as, 0, bid) {
+			t.Fatalf("RandomLegalBid = %v, %v, want a legal bid", bid, ok)
+		}
+		seen[bid]++
+	}
+	if len(seen) != len(want) {
+		t.Errorf("RandomLegalBid drew %v, want every bid of %v", seen, want)
+	}
+}
@@ -0,0 +1,189 @@
+package game
+
+import "fmt"
+
+// Record is the complete log of one game: every auction with its jewel and the actions taken,
+// plus the final result. It is enough to replay the game (Replay) and is stored as JSON by the
+// data tools.
+type Record struct {
+	Seed     int64           `json:"seed"`            // 宝石列の seed (分かっている場合)
+	Rules    Rules           `json:"rules"`           // 対局のルール設定
+	Start    *GameState      `json:"start,omitempty"` // 途中局面から始めた場合の開始局面 (nil なら初期局面)
+	Players  []string        `json:"players"`         // 各席の AI (spec が分かっていれば ParseAISpec の形式、なければ GetName)
+	Auctions []AuctionRecord `json:"auctions"`
+	Scores   []int           `json:"scores"`            // 最終得点 (ボーナスを含まない)
+	Bonuses  []int           `json:"bonuses,omitempty"` // セット収集ボーナス (Rules.Bonus があるときだけ)
+	Moneys   [][3]int        `json:"moneys"`            // 最終所持コイン
+	Ranks    []int           `json:"ranks"`             // 最終順位 (Ranks)
+}
+
+// AuctionRecord logs one auction.
+type AuctionRecord struct {
+	Phase     int         `json:"phase"`
+	Round     int         `json:"round"`
+	Jewel     Jewel       `json:"jewel"`
+	Bids      []BidRecord `json:"bids"`                // 手番順の行動
+	Exchanges []Exchange  `json:"exchanges,omitempty"` // オークションの前に行われた両替 (両替ルール)
+	Sales     []Sale      `json:"sales,omitempty"`     // オークションの後、フェーズ終了時に行われた売却 (売却ルール)
+	Winner    int         `json:"winner"`              // 落札者 (落札なしは -1)
+	Price     [3]int      `json:"price"`               // 落札者が支払った額
+}
+
+// BidRecord is one action in an auction.
+type BidRecord struct {
+	Player int    `json:"player"`
+	Bid    [3]int `json:"bid"`   // AI が返した提示額
+	Valid  bool   `json:"valid"` // false なら降りた扱い (パスまたは不正な入札)
+}
+
+// RecordGame plays a full game like PlayGame and returns its record. ActionHook is not called.
+func RecordGame(ais []AI, nextJewel func() *Jewel) *Record {
+	rec := RecordGameFrom(StartGame(len(ais), Rules{}), ais, nextJewel)
+	rec.Start = nil
+	return rec
+}
+
+// RecordGameFrom plays the rest of a game from gs like PlayGameFrom and returns its record,
+// with gs.Rules and a copy of gs as the start position. gs is updated in place. Players holds
+// the names returned by GetName; callers that created the AIs from specs should replace them
+// with the specs so that the parameters are kept.
+func RecordGameFrom(gs *GameState, ais []AI, nextJewel func() *Jewel) *Record {
+	N := len(ais)
+	rec := &Record{Rules: gs.Rules, Players: make([]string, N), Start: gs.Copy()}
+	for i, ai := range ais {
+		rec.Players[i] = ai.GetName()
+	}
+	for {
+		jewel := gs.DrawJewel(nextJewel)
+		ar := AuctionRecord{Phase: gs.Phase, Round: gs.Round, Jewel: *jewel}
+		hook := func(as *AuctionState, _ *Jewel, player int, bid [3]int) {
+			valid := IsValidBid(bid, as.MaxValue) && HasEnoughMoney(gs.Moneys[player], bid)
+			ar.Bids = append(ar.Bids, BidRecord{Player: player, Bid: bid, Valid: valid})
+		}
+		as := NewAuctionState((gs.Round-1)%N, N)
+		for !gs.stepAuction(as, jewel, ais, hook) {
+		}
+		ar.Winner, ar.Price, ar.Exchanges, ar.Sales = as.MaxPlayer, as.Price, as.Exchanges, as.Sales
+		rec.Auctions = append(rec.Auctions, ar)
+		if !gs.NextAuction() {
+			break
+		}
+	}
+	rec.Scores = append([]int(nil), gs.Scores...)
+	rec.Moneys = append([][3]int(nil), gs.Moneys...)
+	if gs.Rules.Bonus != "" {
+		rec.Bonuses = make([]int, N)
+		for i := range rec.Bonuses {
+			rec.Bonuses[i] = gs.Bonus(i)
+		}
+	}
+	rec.Ranks = Ranks(gs)
+	return rec
+}
+
+// Replay plays r again from its start position. visit, if not nil, is called before every
+// recorded action with the position the acting player saw; the states must not be modified.
+// It returns the final state, or an error if the record does not fit the rules.
+func (r *Record) Replay(visit func(gs *GameState, as *AuctionState, jewel *Jewel, b BidRecord)) (*GameState, error) {
+	N := len(r.Players)
+	var gs *GameState
+	if r.Start != nil {
+		if len(r.Start.Scores) != N {
+			return nil, fmt.Errorf("record: start position has %d players, expected %d", len(r.Start.Scores), N)
+		}
+		gs = r.Start.Copy()
+		gs.Rules = r.Rules
+	} else {
+		gs = StartGame(N, r.Rules)
+	}
+	// 宝石は記録された順に引く (先読みで公開済みの分は飛ばす)
+	drawn := len(gs.Upcoming)
+	recorded := func() *Jewel {
+		j := Jewel{Point: -1 << 31} // 記録が足りない場合は下で不一致になる
+		if drawn < len(r.Auctions) {
+			j = r.Auctions[drawn].Jewel
+		}
+		drawn++
+		return &j
+	}
+	for a := range r.Auctions {
+		ar := &r.Auctions[a]
+		if ar.Phase != gs.Phase || ar.Round != gs.Round {
+			return nil, fmt.Errorf("record: auction %d is phase %d round %d, expected phase %d round %d", a, ar.Phase, ar.Round, gs.Phase, gs.Round)
+		}
+		jewel := *gs.DrawJewel(recorded)
+		if jewel != ar.Jewel {
+			return nil, fmt.Errorf("record: auction %d jewel does not match the jewel sequence", a)
+		}
+		next, failed := 0, error(nil)
+		policy := Policy(func(gs *GameState, as *AuctionState, jewel *Jewel) [3]int {
+			if next >= len(ar.Bids) || ar.Bids[next].Player != as.Turn {
+				failed = fmt.Errorf("record: auction %d: action %d does not match player %d to move", a, next, as.Turn)
+				return [3]int{}
+			}
+			b := ar.Bids[next]
+			next++
+			if visit != nil {
+				visit(gs, as, jewel, b)
+			}
+			return b.Bid
+		})
+		policies := make([]AI, N)
+		for i := range policies {
+			policies[i] = replayer{policy, ar.Exchanges, ar.Sales}
+		}
+		as := NewAuctionState((gs.Round-1)%N, N)
+		for !gs.stepAuction(as, &jewel, policies, nil) {
+			if failed != nil {
+				return nil, failed
+			}
+		}
+		if failed != nil {
+			return nil, failed
+		}
+		if next != len(ar.Bids) || as.MaxPlayer != ar.Winner || as.Price != ar.Price || len(as.Exchanges) != len(ar.Exchanges) || len(as.Sales) != len(ar.Sales) {
+			return nil, fmt.Errorf("record: auction %d does not replay to its recorded outcome", a)
+		}
+		if a < len(r.Auctions)-1 && !gs.NextAuction() {
+			return nil, fmt.Errorf("record: %d auctions recorded, game ends after %d", len(r.Auctions), a+1)
+		}
+	}
+	return gs, nil
+}
+
+// replayer plays recorded bids through Policy, recorded Dutch decisions through AcceptAsk (so
+// that visits see the asking price rather than the SelectAction fallback), and recorded exchanges
+// and sales through SelectExchanges and SelectSale.
+type replayer struct {
+	Policy
+	exchanges []Exchange
+	sales     []Sale
+}
+
+func (r replayer) AcceptAsk(gs *GameState, as *AuctionState, jewel *Jewel) bool {
+	return r.Policy(gs, as, jewel) == as.Asking
+}
+
+func (r replayer) SelectExchanges(gs *GameState, jewel *Jewel, me int) []Exchange {
+	var mine []Exchange
+	for _, e := range r.exchanges {
+		if e.Player == me {
+			mine = append(mine, e)
+		}
+	}
+	return mine
+}
+
+func (r replayer) SelectSale(gs *GameState, me int) int {
+	for _, s := range r.sales {
+		if s.Player != me {
+			continue
+		}
+		for k, j := range gs.Owned[me] {
+			if j == s.Jewel {
+				return k
+			}
+		}
+	}
+	return -1
+}
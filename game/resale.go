@@ -0,0 +1,71 @@
+package game
+
+// Sale is one jewel sold back to the bank under the resale rule.
+type Sale struct {
+	Player int    `json:"player"`
+	Jewel  Jewel  `json:"jewel"`
+	Price  [3]int `json:"price"` // 銀行から受け取った額 (負の得点の宝石では支払った額が負で入る)
+}
+
+// Seller is implemented by AIs that sell jewels when the resale rule is on.
+// AIs without it never sell.
+type Seller interface {
+	// SelectSale returns the index in gameState.Owned[me] of the jewel player me sells at the end
+	// of the phase, or -1 to keep everything. Invalid or unaffordable choices keep everything.
+	SelectSale(gameState *GameState, me int) int
+}
+
+// ResalePrice returns the coins the bank pays for jewel under g.Rules.Resale: Resale per point
+// of the jewel. A jewel with negative points has a negative price, so getting rid of it costs.
+func (g *GameState) ResalePrice(jewel Jewel) [3]int {
+	var price [3]int
+	for c := 0; c < 3; c++ {
+		price[c] = jewel.Point * g.Rules.Resale[c]
+	}
+	return price
+}
+
+// CanSell reports whether player can sell their k-th owned jewel.
+func (g *GameState) CanSell(player, k int) bool {
+	if g.Rules.Resale == [3]int{} || player >= len(g.Owned) || k < 0 || k >= len(g.Owned[player]) {
+		return false
+	}
+	price := g.ResalePrice(g.Owned[player][k])
+	for c := 0; c < 3; c++ {
+		if g.Moneys[player][c]+price[c] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// resaleDue reports whether the auction that just completed was the last one of its phase and
+// the players may sell a jewel.
+func (g *GameState) resaleDue() bool {
+	return g.Rules.Resale != [3]int{} && g.Round == 3*len(g.Scores)
+}
+
+// sellJewels asks every player in seat order which jewel to sell and sells it: the player
+// receives ResalePrice and loses the jewel's points and income. The sales are appended to
+// as.Sales.
+func (g *GameState) sellJewels(as *AuctionState, ais []AI) {
+	for player, ai := range ais {
+		s, ok := ai.(Seller)
+		if !ok {
+			continue
+		}
+		k := s.SelectSale(g, player)
+		if !g.CanSell(player, k) {
+			continue
+		}
+		jewel := g.Owned[player][k]
+		price := g.ResalePrice(jewel)
+		for c := 0; c < 3; c++ {
+			g.Moneys[player][c] += price[c]
+			g.Incomes[player][c] -= jewel.Income[c]
+		}
+		g.Scores[player] -= jewel.Point
+		g.Owned[player] = append(g.Owned[player][:k:k], g.Owned[player][k+1:]...)
+		as.Sales = append(as.Sales, Sale{Player: player, Jewel: jewel, Price: price})
+	}
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+// resaleGame returns a two-player game on the last auction of phase 2 in which player 0 owns
+// owned, with the matching score and income.
+func resaleGame(resale [3]int, owned ...Jewel) *GameState {
+	g := NewGameState(2)
+	g.Rules = Rules{Resale: resale}
+	g.Phase, g.Round = 2, 6
+	g.Moneys = [][3]int{{5, 5, 5}, {5, 5, 5}}
+	for _, j := range owned {
+		g.Owned[0] = append(g.Owned[0], j)
+		g.Scores[0] += j.Point
+		for c := 0; c < 3; c++ {
+			g.Incomes[0][c] += j.Income[c]
+		}
+	}
+	return g
+}
+
+func TestResaleDue(t *testing.T) {
+	g := resaleGame([3]int{1, 0, 0})
+	if !g.resaleDue() {
+		t.Errorf("resaleDue = false on the last auction of the phase")
+	}
+	g.Round = 5
+	if g.resaleDue() {
+		t.Errorf("resaleDue = true before the last auction of the phase")
+	}
+	g = resaleGame([3]int{})
+	if g.resaleDue() {
+		t.Errorf("resaleDue = true with the rule off")
+	}
+}
+
+func TestSellJewel(t *testing.T) {
+	a := Jewel{Point: 3, Income: [3]int{1, 0, 0}}
+	b := Jewel{Point: 2, Income: [3]int{0, 2, 0}}
+	g := resaleGame([3]int{1, 0, 2}, a, b)
+	as := NewAuctionState(1, 2)
+	// 席 0 は 2 個目を売る。席 1 は何も持っていないので売れない
+	for !g.StepAuction(as, &Jewel{Point: 1}, []AI{trader{sale: 1}, trader{sale: 0}}) {
+	}
+
+	if g.Moneys[0] != [3]int{7, 5, 9} {
+		t.Errorf("seller coins = %v, want [7 5 9]", g.Moneys[0])
+	}
+	if g.Scores[0] != 3 || g.Incomes[0] != [3]int{1, 0, 0} {
+		t.Errorf("seller score %d and income %v, want 3 and [1 0 0]", g.Scores[0], g.Incomes[0])
+	}
+	if !reflect.DeepEqual(g.Owned[0], []Jewel{a}) {
+		t.Errorf("seller owns %v, want only %v", g.Owned[0], a)
+	}
+	want := []Sale{{Player: 0, Jewel: b, Price: [3]int{2, 0, 4}}}
+	if !reflect.DeepEqual(as.Sales, want) {
+		t.Errorf("sales = %v, want %v", as.Sales, want)
+	}
+	if g.Moneys[1] != [3]int{5, 5, 5} {
+		t.Errorf("player without jewels has coins %v, want [5 5 5]", g.Moneys[1])
+	}
+}
+
+func TestSellCursedJewel(t *testing.T) {
+	// 負の得点の宝石は買い取り額も負で、コインを払って手放し、失った負の得点の分だけ得点が増える
+	cursed := Jewel{Point: -2}
+	g := resaleGame([3]int{2, 0, 0}, cursed)
+	if price := g.ResalePrice(cursed); price != [3]int{-4, 0, 0} {
+		t.Errorf("ResalePrice = %v, want [-4 0 0]", price)
+	}
+	as := NewAuctionState(1, 2)
+	for !g.StepAuction(as, &Jewel{Point: 1}, []AI{trader{sale: 0}, fixedBid{}}) {
+	}
+	if g.Moneys[0] != [3]int{1, 5, 5} || g.Scores[0] != 0 || len(g.Owned[0]) != 0 {
+		t.Errorf("after selling: coins %v, score %d, owned %v, want [1 5 5], 0 and nothing", g.Moneys[0], g.Scores[0], g.Owned[0])
+	}
+	if len(as.Sales) != 1 || as.Sales[0].Price != [3]int{-4, 0, 0} {
+		t.Errorf("sales = %v, want one sale at [-4 0 0]", as.Sales)
+	}
+
+	// 払えなければ手放せない
+	g = resaleGame([3]int{3, 0, 0}, cursed)
+	if g.CanSell(0, 0) {
+		t.Errorf("CanSell allowed paying 6 red coins with 5")
+	}
+	as = NewAuctionState(1, 2)
+	for !g.StepAuction(as, &Jewel{Point: 1}, []AI{trader{sale: 0}, fixedBid{}}) {
+	}
+	if len(as.Sales) != 0 || g.Scores[0] != -2 || g.Moneys[0] != [3]int{5, 5, 5} {
+		t.Errorf("unaffordable sale was made: sales %v, score %d, coins %v", as.Sales, g.Scores[0], g.Moneys[0])
+	}
+}
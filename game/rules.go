@@ -0,0 +1,157 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Rules is the rules configuration of a game. The zero value is the standard game.
+// It is stored as JSON so that experiments can be described in files.
+//
+// Under PaymentSecond the reserve price is zero: a winner without rivals pays nothing.
+type Rules struct {
+	Jewels  string `json:"jewels,omitempty"`  // 宝石の生成規則の名前 (generator.Lookup、空なら "uniform")
+	Preview int    `json:"preview,omitempty"` // 先読みルール: 全員に公開する次以降の宝石の数 (0 なら公開しない)
+
+	Auction string     `json:"auction,omitempty"` // オークション方式 (AuctionOpen または AuctionSealed、空なら AuctionOpen)
+	Compare string     `json:"compare,omitempty"` // 封印入札と 2 位価格での入札の比較規則 (CompareSum など、空なら CompareSum)
+	Weights [3]float64 `json:"weights,omitempty"` // Compare が CompareWeighted のときの各色の重み
+	Payment string     `json:"payment,omitempty"` // 落札者の支払い方式 (PaymentFirst または PaymentSecond、空なら PaymentFirst)
+
+	DutchMix   [3]int `json:"dutch_mix,omitempty"`   // ダッチオークションの価格 1 段階あたりの各色の枚数 (ゼロなら {1,1,1})
+	DutchStart int    `json:"dutch_start,omitempty"` // ダッチオークションの開始段階 (0 なら誰かが払える最高の段階)
+
+	Teams []int `json:"teams,omitempty"` // チーム戦: 席ごとのチーム ID (空なら個人戦、ValidateFor で人数と照合)
+
+	Exchange int `json:"exchange,omitempty"` // 両替ルール: フェーズ開始時に、この枚数で別の色 1 枚に両替できる (0 なら両替なし)
+
+	Resale [3]int `json:"resale,omitempty"` // 売却ルール: フェーズ終了時に宝石 1 個を売ったときに銀行が払う得点 1 あたりの各色の枚数 (ゼロなら売却なし)
+
+	Bonus string `json:"bonus,omitempty"` // セット収集ボーナスの Scorer の名前 (LookupScorer、空ならボーナスなし)
+}
+
+// Auction mechanisms.
+const (
+	AuctionOpen   = "open"   // 親から順に競り上げる (標準)
+	AuctionSealed = "sealed" // 全員が一斉に 1 回だけ封印入札する
+	AuctionDutch  = "dutch"  // 提示価格を下げていき、最初に受け入れた人が買う
+)
+
+// Comparison rules for multi-color bids.
+const (
+	CompareSum           = "sum"           // 合計枚数
+	CompareLexicographic = "lexicographic" // 赤、緑、青の順の辞書式
+	CompareWeighted      = "weighted"      // Weights による重み付き和
+)
+
+// Payment rules.
+const (
+	PaymentFirst  = "first"  // 自分の入札額を支払う (標準)
+	PaymentSecond = "second" // 2 位の入札額を支払う (Vickrey 方式)
+)
+
+// OpenAuction reports whether r uses the open (ascending) auction, the default.
+func (r Rules) OpenAuction() bool {
+	return r.Auction == "" || r.Auction == AuctionOpen
+}
+
+// Validate checks that every named option of r exists.
+func (r Rules) Validate() error {
+	switch r.Auction {
+	case "", AuctionOpen, AuctionSealed, AuctionDutch:
+	default:
+		return fmt.Errorf("rules: unknown auction %q", r.Auction)
+	}
+	switch r.Compare {
+	case "", CompareSum, CompareLexicographic, CompareWeighted:
+	default:
+		return fmt.Errorf("rules: unknown compare %q", r.Compare)
+	}
+	if r.Compare == CompareWeighted && r.Weights == [3]float64{} {
+		return fmt.Errorf("rules: weighted compare needs a non-zero weight")
+	}
+	switch r.Payment {
+	case "", PaymentFirst, PaymentSecond:
+	default:
+		return fmt.Errorf("rules: unknown payment %q", r.Payment)
+	}
+	if r.Auction == AuctionDutch && r.Payment == PaymentSecond {
+		return fmt.Errorf("rules: dutch auctions have no second-highest bid")
+	}
+	for c := 0; c < 3; c++ {
+		if r.DutchMix[c] < 0 {
+			return fmt.Errorf("rules: dutch_mix must not be negative")
+		}
+	}
+	for c := 0; c < 3; c++ {
+		if r.Resale[c] < 0 {
+			return fmt.Errorf("rules: resale must not be negative")
+		}
+	}
+	if _, err := LookupScorer(r.Bonus); err != nil {
+		return fmt.Errorf("rules: %v", err)
+	}
+	if r.Exchange < 0 {
+		return fmt.Errorf("rules: exchange must not be negative")
+	}
+	if r.DutchStart < 0 {
+		return fmt.Errorf("rules: dutch_start must not be negative")
+	}
+	if r.Preview < 0 {
+		return fmt.Errorf("rules: preview must not be negative")
+	}
+	return nil
+}
+
+// StartGame returns the position at the start of the first auction of a game under rules:
+// NewGameState with the rules attached and the first phase's income paid.
+func StartGame(N int, rules Rules) *GameState {
+	gs := NewGameState(N)
+	gs.Rules = rules
+	gs.ApplyPhaseIncome()
+	return gs
+}
+
+// LoadRules reads a rules configuration from a JSON file.
+func LoadRules(path string) (Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Rules{}, err
+	}
+	return ParseRules(data)
+}
+
+// LoadRulesOverride is the rules loading of the command line tools: the rules in the JSON file
+// path, or the standard rules if path is empty, with the jewel generator replaced by jewels
+// unless it is empty.
+func LoadRulesOverride(path, jewels string) (Rules, error) {
+	var rules Rules
+	if path != "" {
+		r, err := LoadRules(path)
+		if err != nil {
+			return rules, err
+		}
+		rules = r
+	}
+	if jewels != "" {
+		rules.Jewels = jewels
+	}
+	return rules, nil
+}
+
+// ParseRules decodes a rules configuration from JSON. Unknown fields are rejected so that
+// typos do not silently fall back to the standard rules.
+func ParseRules(data []byte) (Rules, error) {
+	var r Rules
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&r); err != nil {
+		return Rules{}, fmt.Errorf("rules: %v", err)
+	}
+	if err := r.Validate(); err != nil {
+		return Rules{}, err
+	}
+	return r, nil
+}
@@ -1,5 +1,7 @@
 package game
 
+import "sort"
+
 // NewGameState initializes and returns a GameState for N players.
 // Each player starts with 10 coins of each color, zero score, and zero income.
 func NewGameState(N int) *GameState {
@@ -15,6 +17,7 @@ func NewGameState(N int) *GameState {
 		Scores:  scores,
 		Incomes: incomes,
 		Moneys:  moneys,
+		Owned:   make([][]Jewel, N),
 	}
 }
 
@@ -43,3 +46,57 @@ func (g *GameState) AdvanceRound() {
 		g.Round = 1
 	}
 }
+
+// NextAuction advances to the next round like AdvanceRound and reports whether another auction
+// remains. After the last round of the final phase the state is left unchanged and false is returned.
+func (g *GameState) NextAuction() bool {
+	if g.Phase >= NumPhases && g.Round >= 3*len(g.Scores) {
+		return false
+	}
+	g.AdvanceRound()
+	return true
+}
+
+// RemainingAuctions returns the number of auctions left in the game, counting the current one.
+func (g *GameState) RemainingAuctions() int {
+	rounds := 3 * len(g.Scores)
+	return (NumPhases-g.Phase)*rounds + rounds - g.Round + 1
+}
+
+// Ranks returns the standing of each player (1 = best).
+// Players are ordered by score including the set-collection bonus (TotalScores), then by the
+// total of their three coin colors; players equal on both share the same rank. In a team game
+// the teams are ordered the same way by their summed score and coins (TeamResults), and every
+// member of a team gets 1 plus the number of players on better teams.
+func Ranks(g *GameState) []int {
+	if g.HasTeams() {
+		return teamRanks(g)
+	}
+	N := len(g.Scores)
+	scores := TotalScores(g)
+	type pair struct{ idx, score, moneySum int }
+	arr := make([]pair, N)
+	for i := range arr {
+		sum := g.Moneys[i][0] + g.Moneys[i][1] + g.Moneys[i][2]
+		arr[i] = pair{i, scores[i], sum}
+	}
+	sort.Slice(arr, func(i, j int) bool {
+		if arr[i].score != arr[j].score {
+			return arr[i].score > arr[j].score
+		}
+		return arr[i].moneySum > arr[j].moneySum
+	})
+	ranks := make([]int, N)
+	if N == 0 {
+		return ranks
+	}
+	rank := 1
+	ranks[arr[0].idx] = rank
+	for i := 1; i < len(arr); i++ {
+		if arr[i].score != arr[i-1].score || arr[i].moneySum != arr[i-1].moneySum {
+			rank = i + 1
+		}
+		ranks[arr[i].idx] = rank
+	}
+	return ranks
+}
@@ -0,0 +1,114 @@
+package game
+
+import "sort"
+
+// SealedBidder is implemented by AIs that know how to bid in sealed-bid auctions.
+// AIs without it are asked with SelectAction on an auction with no bids yet, where Turn is
+// their own seat, and their opening bid is used as the sealed bid.
+type SealedBidder interface {
+	// SelectSealedBid returns the hidden bid of player me; {0,0,0} means no bid.
+	SelectSealedBid(gameState *GameState, jewel *Jewel, me int) [3]int
+}
+
+// CompareBids orders two bids under r.Compare and returns -1, 0 or +1.
+func (r Rules) CompareBids(a, b [3]int) int {
+	switch r.Compare {
+	case CompareLexicographic:
+		for c := 0; c < 3; c++ {
+			if a[c] != b[c] {
+				return sign(float64(a[c] - b[c]))
+			}
+		}
+		return 0
+	case CompareWeighted:
+		va, vb := 0.0, 0.0
+		for c := 0; c < 3; c++ {
+			va += r.Weights[c] * float64(a[c])
+			vb += r.Weights[c] * float64(b[c])
+		}
+		return sign(va - vb)
+	}
+	return sign(float64(a[0] + a[1] + a[2] - b[0] - b[1] - b[2]))
+}
+
+// ColorPriority returns the colors from the most to the least significant under r.Compare:
+// red, green, blue for the sum and lexicographic comparison, and by decreasing weight (ties in
+// that order) for the weighted comparison.
+func (r Rules) ColorPriority() [3]int {
+	order := [3]int{0, 1, 2}
+	if r.Compare == CompareWeighted {
+		s := order[:]
+		sort.SliceStable(s, func(i, j int) bool { return r.Weights[s[i]] > r.Weights[s[j]] })
+	}
+	return order
+}
+
+func sign(x float64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+// SealedBid returns the hidden bid of ai, the player at as.Turn: SelectSealedBid if ai is a
+// SealedBidder, and otherwise its SelectAction on as, which must have no bids yet.
+func (g *GameState) SealedBid(ai AI, as *AuctionState, jewel *Jewel) [3]int {
+	if sb, ok := ai.(SealedBidder); ok {
+		return sb.SelectSealedBid(g, jewel, as.Turn)
+	}
+	return ai.SelectAction(g, as, jewel)
+}
+
+// stepSealed collects one bid from every active player, starting at the parent (as.Turn), and
+// settles the auction. A bid counts if it is non-zero and affordable. The highest bid under
+// CompareBids wins; ties go to the player closest to the parent in seat order.
+func (g *GameState) stepSealed(as *AuctionState, jewel *Jewel, ais []AI, hook func(*AuctionState, *Jewel, int, [3]int)) bool {
+	N := len(g.Scores)
+	parent := as.Turn
+	var valid [][3]int
+	var bidders []int
+	for k := 0; k < N; k++ {
+		player := (parent + k) % N
+		if !as.Active[player] {
+			continue
+		}
+		as.Turn = player
+		bid := g.SealedBid(ais[player], as, jewel)
+		if hook != nil {
+			hook(as, jewel, player, bid)
+		}
+		if IsValidBid(bid, [3]int{}) && HasEnoughMoney(g.Moneys[player], bid) {
+			valid = append(valid, bid)
+			bidders = append(bidders, player)
+		}
+	}
+	as.Turn = parent
+	// 入札しなかった席は、全員の入札が揃ってから降りた扱いにする
+	for player := range as.Active {
+		as.Active[player] = false
+	}
+	for _, player := range bidders {
+		as.Active[player] = true
+	}
+	as.activeCount = len(bidders)
+
+	best, second := -1, -1
+	for i := range valid {
+		if best < 0 || g.Rules.CompareBids(valid[i], valid[best]) > 0 {
+			best, second = i, best
+		} else if second < 0 || g.Rules.CompareBids(valid[i], valid[second]) > 0 {
+			second = i
+		}
+	}
+	if best >= 0 {
+		as.MaxPlayer, as.MaxValue = bidders[best], valid[best]
+		if second >= 0 {
+			as.SecondValue = valid[second]
+		}
+		g.settle(as, jewel)
+	}
+	return true
+}
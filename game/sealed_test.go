@@ -0,0 +1,124 @@
+package game
+
+import "testing"
+
+// sealedBid bids a fixed amount through SealedBidder and never bids in open auctions.
+type sealedBid [3]int
+
+func (b sealedBid) GetName() string { return "sealed" }
+
+func (b sealedBid) SelectAction(*GameState, *AuctionState, *Jewel) [3]int { return [3]int{} }
+
+func (b sealedBid) SelectSealedBid(gs *GameState, jewel *Jewel, me int) [3]int { return b }
+
+func TestCompareBids(t *testing.T) {
+	sum := Rules{}
+	lex := Rules{Compare: CompareLexicographic}
+	weighted := Rules{Compare: CompareWeighted, Weights: [3]float64{1, 0.5, 2}}
+	tests := []struct {
+		name  string
+		rules Rules
+		a, b  [3]int
+		want  int
+	}{
+		{"sum greater", sum, [3]int{0, 0, 4}, [3]int{1, 1, 1}, 1},
+		{"sum tie", sum, [3]int{3, 0, 0}, [3]int{0, 1, 2}, 0},
+		{"sum less", sum, [3]int{1, 0, 0}, [3]int{0, 1, 1}, -1},
+		{"lexicographic red first", lex, [3]int{1, 0, 0}, [3]int{0, 9, 9}, 1},
+		{"lexicographic green breaks tie", lex, [3]int{2, 1, 0}, [3]int{2, 3, 0}, -1},
+		{"lexicographic equal", lex, [3]int{2, 1, 4}, [3]int{2, 1, 4}, 0},
+		{"weighted greater", weighted, [3]int{0, 0, 1}, [3]int{1, 1, 0}, 1},
+		{"weighted tie", weighted, [3]int{0, 2, 0}, [3]int{1, 0, 0}, 0},
+		{"weighted less", weighted, [3]int{0, 3, 0}, [3]int{0, 0, 1}, -1},
+	}
+	for _, tt := range tests {
+		if got := tt.rules.CompareBids(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: CompareBids(%v, %v) = %d, want %d", tt.name, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestColorPriority(t *testing.T) {
+	if got := (Rules{Compare: CompareLexicographic}).ColorPriority(); got != [3]int{0, 1, 2} {
+		t.Errorf("lexicographic ColorPriority = %v, want [0 1 2]", got)
+	}
+	weighted := Rules{Compare: CompareWeighted, Weights: [3]float64{1, 2, 1}}
+	if got := weighted.ColorPriority(); got != [3]int{1, 0, 2} {
+		t.Errorf("weighted ColorPriority = %v, want [1 0 2]", got)
+	}
+}
+
+func TestSealedTieGoesToParent(t *testing.T) {
+	// 合計で同点の入札は、親から席順で近いプレイヤーが勝つ
+	ais := []AI{sealedBid{2, 0, 0}, sealedBid{0, 2, 0}, sealedBid{1, 0, 1}}
+	for parent, want := range []int{0, 1, 2} {
+		g := NewGameState(3)
+		g.Rules = Rules{Auction: AuctionSealed}
+		as := NewAuctionState(parent, 3)
+		if !g.StepAuction(as, &Jewel{Point: 1}, ais) {
+			t.Fatalf("sealed auction did not finish in one step")
+		}
+		if as.MaxPlayer != want || as.Turn != parent {
+			t.Errorf("parent %d: winner %d, turn %d, want winner %d and the turn left at the parent", parent, as.MaxPlayer, as.Turn, want)
+		}
+	}
+}
+
+func TestSealedHighestBidWins(t *testing.T) {
+	g := NewGameState(3)
+	g.Rules = Rules{Auction: AuctionSealed, Compare: CompareLexicographic}
+	// 席 2 の入札は所持コインを超えるので入札なし
+	ais := []AI{sealedBid{1, 5, 5}, sealedBid{2, 0, 0}, sealedBid{9, 11, 0}}
+	as := NewAuctionState(0, 3)
+	g.StepAuction(as, &Jewel{Point: 2}, ais)
+	if as.MaxPlayer != 1 || as.Price != [3]int{2, 0, 0} || as.SecondValue != [3]int{1, 5, 5} {
+		t.Errorf("winner %d paid %v with second bid %v, want seat 1 paying [2 0 0] over [1 5 5]", as.MaxPlayer, as.Price, as.SecondValue)
+	}
+	if !as.Active[0] || !as.Active[1] || as.Active[2] {
+		t.Errorf("active = %v, want only the valid bidders", as.Active)
+	}
+}
+
+// turnRecorder bids one red coin through SelectAction and records the auction it was shown.
+type turnRecorder struct {
+	seen *[]AuctionState
+}
+
+func (r turnRecorder) GetName() string { return "recorder" }
+
+func (r turnRecorder) SelectAction(gs *GameState, as *AuctionState, jewel *Jewel) [3]int {
+	*r.seen = append(*r.seen, *as)
+	return [3]int{1, 0, 0}
+}
+
+func TestSealedSelectActionFallback(t *testing.T) {
+	var seen []AuctionState
+	g := NewGameState(3)
+	g.Rules = Rules{Auction: AuctionSealed}
+	ais := []AI{turnRecorder{&seen}, sealedBid{0, 2, 0}, turnRecorder{&seen}}
+	as := NewAuctionState(1, 3)
+	g.StepAuction(as, &Jewel{Point: 1}, ais)
+	if len(seen) != 2 || seen[0].Turn != 2 || seen[1].Turn != 0 {
+		t.Fatalf("SelectAction was asked %d times at turns %v, want seats 2 then 0", len(seen), seen)
+	}
+	for _, s := range seen {
+		if s.MaxPlayer != -1 || s.MaxValue != [3]int{} {
+			t.Errorf("fallback saw a bid of %v by %d, want an auction with no bids", s.MaxValue, s.MaxPlayer)
+		}
+	}
+	if as.MaxPlayer != 1 {
+		t.Errorf("winner %d, want seat 1 with the highest sum", as.MaxPlayer)
+	}
+}
+
+func TestValidateWeightedCompare(t *testing.T) {
+	if err := (Rules{Compare: CompareWeighted}).Validate(); err == nil {
+		t.Errorf("Validate accepted weighted compare with all-zero weights")
+	}
+	if err := (Rules{Compare: CompareWeighted, Weights: [3]float64{0, 0, 1}}).Validate(); err != nil {
+		t.Errorf("Validate rejected weighted compare with a weight: %v", err)
+	}
+	if _, err := ParseRules([]byte(`{"auction":"sealed","compare":"weighted"}`)); err == nil {
+		t.Errorf("ParseRules accepted weighted compare without weights")
+	}
+}
@@ -0,0 +1,147 @@
+package game
+
+// Clone returns a deep copy of as, including the bookkeeping StepAuction keeps internally.
+func (as *AuctionState) Clone() *AuctionState {
+	c := *as
+	c.Active = append([]bool(nil), as.Active...)
+	c.Exchanges = append([]Exchange(nil), as.Exchanges...)
+	c.Sales = append([]Sale(nil), as.Sales...)
+	return &c
+}
+
+// Policy is a bidding function with the signature of AI.SelectAction.
+// It lets search code plug plain functions in wherever an AI is expected.
+type Policy func(gs *GameState, as *AuctionState, jewel *Jewel) [3]int
+
+// GetName implements AI.
+func (p Policy) GetName() string { return "Policy" }
+
+// SelectAction implements AI by calling p.
+func (p Policy) SelectAction(gs *GameState, as *AuctionState, jewel *Jewel) [3]int {
+	return p(gs, as, jewel)
+}
+
+// Simulator rolls a game forward from an arbitrary position for search-based AIs.
+// It owns private copies of the states it is given, never calls ActionHook, and can be cloned
+// cheaply at any point to explore alternative continuations.
+type Simulator struct {
+	State   *GameState
+	Auction *AuctionState // 進行中のオークション (ゲーム終了後は nil)
+	Jewel   *Jewel        // 進行中のオークションの宝石
+
+	// Policies は各席の方策。Step と Run で使われる
+	Policies []AI
+	// NextJewel は以降のオークションの宝石を返す (未来の宝石のサンプリングに使う、nil は不可)
+	NextJewel func() *Jewel
+}
+
+// NewSimulator starts a simulation from copies of gs and as with jewel on sale.
+// A nil as starts a fresh auction of jewel in the current round. It panics if nextJewel is nil,
+// since every auction after the current one needs a jewel.
+func NewSimulator(gs *GameState, as *AuctionState, jewel *Jewel, policies []AI, nextJewel func() *Jewel) *Simulator {
+	if nextJewel == nil {
+		panic("game: NewSimulator with nil nextJewel")
+	}
+	N := len(gs.Scores)
+	s := &Simulator{State: gs.Copy(), Jewel: jewel, Policies: policies, NextJewel: nextJewel}
+	if as != nil {
+		s.Auction = as.Clone()
+	} else {
+		s.Auction = NewAuctionState((gs.Round-1)%N, N)
+	}
+	return s
+}
+
+// Clone returns an independent copy of the simulation. Policies and NextJewel are shared.
+func (s *Simulator) Clone() *Simulator {
+	c := *s
+	c.State = s.State.Copy()
+	if s.Auction != nil {
+		c.Auction = s.Auction.Clone()
+	}
+	return &c
+}
+
+// Done reports whether the game has ended.
+func (s *Simulator) Done() bool { return s.Auction == nil }
+
+// Turn returns the player to move in the current auction, or -1 when the game has ended.
+func (s *Simulator) Turn() int {
+	if s.Auction == nil {
+		return -1
+	}
+	return s.Auction.Turn
+}
+
+// Apply plays bid for the player to move instead of asking their policy, then moves on to the
+// next auction if this one finished. It reports whether the current auction finished.
+func (s *Simulator) Apply(bid [3]int) bool {
+	fixed := append([]AI(nil), s.Policies...)
+	fixed[s.Auction.Turn] = override{s.Policies[s.Auction.Turn], bid}
+	return s.step(fixed)
+}
+
+// override bids a fixed bid in place of base, which still makes the exchange and resale
+// decisions if it can.
+type override struct {
+	base AI
+	bid  [3]int
+}
+
+func (o override) GetName() string { return "Policy" }
+
+func (o override) SelectAction(*GameState, *AuctionState, *Jewel) [3]int { return o.bid }
+
+func (o override) SelectExchanges(gs *GameState, jewel *Jewel, me int) []Exchange {
+	if ex, ok := o.base.(Exchanger); ok {
+		return ex.SelectExchanges(gs, jewel, me)
+	}
+	return nil
+}
+
+func (o override) SelectSale(gs *GameState, me int) int {
+	if s, ok := o.base.(Seller); ok {
+		return s.SelectSale(gs, me)
+	}
+	return -1
+}
+
+// Step plays one action of the player to move using their policy and reports whether the
+// current auction finished.
+func (s *Simulator) Step() bool {
+	return s.step(s.Policies)
+}
+
+func (s *Simulator) step(ais []AI) bool {
+	if !s.State.stepAuction(s.Auction, s.Jewel, ais, nil) {
+		return false
+	}
+	s.nextAuction()
+	return true
+}
+
+// nextAuction advances the round (and phase, paying income) and draws the next jewel,
+// or marks the game as ended.
+func (s *Simulator) nextAuction() {
+	if !s.State.NextAuction() {
+		s.Auction = nil
+		return
+	}
+	N := len(s.State.Scores)
+	s.Jewel = s.State.DrawJewel(s.NextJewel)
+	s.Auction = NewAuctionState((s.State.Round-1)%N, N)
+}
+
+// FinishAuction plays the current auction to completion with the policies.
+func (s *Simulator) FinishAuction() {
+	for !s.Done() && !s.Step() {
+	}
+}
+
+// Run plays the game to the end with the policies and returns the final ranks.
+func (s *Simulator) Run() []int {
+	for !s.Done() {
+		s.Step()
+	}
+	return Ranks(s.State)
+}
@@ -0,0 +1,113 @@
+package game
+
+import "testing"
+
+func oneRedJewel() *Jewel { return &Jewel{Point: 1, Income: [3]int{1, 0, 0}} }
+
+// newTestSimulator starts a three-player game in which every player opens with one red coin and
+// otherwise passes.
+func newTestSimulator() *Simulator {
+	gs := StartGame(3, Rules{})
+	policies := []AI{fixedBid{1, 0, 0}, fixedBid{1, 0, 0}, fixedBid{1, 0, 0}}
+	return NewSimulator(gs, nil, oneRedJewel(), policies, oneRedJewel)
+}
+
+func TestNewSimulatorCopiesStates(t *testing.T) {
+	gs := StartGame(3, Rules{})
+	as := NewAuctionState(0, 3)
+	s := NewSimulator(gs, as, oneRedJewel(), []AI{fixedBid{}, fixedBid{}, fixedBid{}}, oneRedJewel)
+	s.Apply([3]int{2, 0, 0})
+	if as.MaxPlayer != -1 || as.Turn != 0 || gs.Moneys[0] != [3]int{12, 10, 10} {
+		t.Errorf("Apply changed the states passed to NewSimulator: %+v, %v", as, gs.Moneys[0])
+	}
+}
+
+func TestNewSimulatorRejectsNilNextJewel(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewSimulator with nil nextJewel did not panic")
+		}
+	}()
+	NewSimulator(StartGame(2, Rules{}), nil, oneRedJewel(), []AI{fixedBid{}, fixedBid{}}, nil)
+}
+
+func TestSimulatorCloneIsIndependent(t *testing.T) {
+	s := newTestSimulator()
+	s.Apply([3]int{1, 0, 0})
+	c := s.Clone()
+	c.Apply([3]int{0, 0, 0}) // 席 1 が降りる
+	c.Apply([3]int{2, 0, 0}) // 席 2 が上乗せする
+
+	if !s.Auction.Active[1] || s.Auction.activeCount != 3 || s.Auction.consecutivePasses != 0 {
+		t.Errorf("original auction changed by its clone: active %v, count %d, passes %d",
+			s.Auction.Active, s.Auction.activeCount, s.Auction.consecutivePasses)
+	}
+	if s.Auction.MaxValue != [3]int{1, 0, 0} || s.Auction.MaxPlayer != 0 || s.Turn() != 1 {
+		t.Errorf("original auction = %v by %d, turn %d, want [1 0 0] by 0, turn 1", s.Auction.MaxValue, s.Auction.MaxPlayer, s.Turn())
+	}
+	if c.Auction.activeCount != 2 || c.Auction.MaxValue != [3]int{2, 0, 0} || c.Auction.MaxPlayer != 2 {
+		t.Errorf("clone auction = %v by %d with %d active, want [2 0 0] by 2 with 2 active",
+			c.Auction.MaxValue, c.Auction.MaxPlayer, c.Auction.activeCount)
+	}
+
+	c.FinishAuction()
+	if s.State.Round != 1 || s.State.Moneys[2] != [3]int{10, 10, 10} || s.State.Scores[2] != 0 {
+		t.Errorf("original game changed by its clone: round %d, moneys %v, scores %v", s.State.Round, s.State.Moneys, s.State.Scores)
+	}
+}
+
+func TestSimulatorApply(t *testing.T) {
+	s := newTestSimulator()
+	if done := s.Apply([3]int{0, 2, 0}); done {
+		t.Fatalf("Apply of an opening bid finished the auction")
+	}
+	if s.Auction.MaxValue != [3]int{0, 2, 0} || s.Auction.MaxPlayer != 0 || s.Turn() != 1 {
+		t.Errorf("after Apply: %v by %d, turn %d, want [0 2 0] by 0, turn 1", s.Auction.MaxValue, s.Auction.MaxPlayer, s.Turn())
+	}
+	s.Apply([3]int{0, 0, 0})
+	if s.Auction.Active[1] || s.Auction.activeCount != 2 {
+		t.Errorf("after a pass: active %v, count %d, want seat 1 out", s.Auction.Active, s.Auction.activeCount)
+	}
+	if done := s.Apply([3]int{0, 0, 0}); !done {
+		t.Fatalf("Apply of the last pass did not finish the auction")
+	}
+	if s.State.Scores[0] != 1 || s.State.Moneys[0] != [3]int{12, 8, 10} {
+		t.Errorf("winner has score %d and coins %v, want 1 and [12 8 10]", s.State.Scores[0], s.State.Moneys[0])
+	}
+	if s.State.Round != 2 || s.Turn() != 1 {
+		t.Errorf("next auction: round %d, turn %d, want round 2 from seat 1", s.State.Round, s.Turn())
+	}
+}
+
+func TestSimulatorFinishAuction(t *testing.T) {
+	s := newTestSimulator()
+	s.FinishAuction()
+	if s.State.Round != 2 || s.Auction.MaxPlayer != -1 {
+		t.Fatalf("FinishAuction left round %d, auction %+v, want a fresh auction in round 2", s.State.Round, s.Auction)
+	}
+	// 親の席 0 が赤 1 枚で開き、他は上回れずに降りる
+	if s.State.Scores[0] != 1 || s.State.Moneys[0] != [3]int{11, 10, 10} || s.State.Incomes[0] != [3]int{1, 0, 0} {
+		t.Errorf("winner has score %d, coins %v, income %v", s.State.Scores[0], s.State.Moneys[0], s.State.Incomes[0])
+	}
+}
+
+func TestSimulatorRun(t *testing.T) {
+	s := newTestSimulator()
+	ranks := s.Run()
+	if !s.Done() || s.Turn() != -1 {
+		t.Fatalf("Run returned before the end of the game")
+	}
+	if s.State.Phase != NumPhases || s.State.Round != 9 {
+		t.Errorf("Run ended in phase %d round %d, want phase %d round 9", s.State.Phase, s.State.Round, NumPhases)
+	}
+	if len(ranks) != 3 {
+		t.Fatalf("Run returned %v, want 3 ranks", ranks)
+	}
+	total := 0
+	for _, sc := range s.State.Scores {
+		total += sc
+	}
+	if total != NumPhases*9 {
+		t.Errorf("players scored %d in total, want %d (every jewel sold)", total, NumPhases*9)
+	}
+}
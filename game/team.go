@@ -0,0 +1,111 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+)
+
+// TeamResult is the standing of one team: the members' scores and coins summed.
+type TeamResult struct {
+	Team    int   // チーム ID
+	Members []int // 所属するプレイヤー (席番号の昇順)
+	Score   int   // 得点 (セット収集ボーナスを含む) の合計
+	Coins   int   // 所持コインの合計 (3 色の合計)
+	Rank    int   // チームの順位 (1 = 最上位、同点のチームは同順位)
+}
+
+// ValidateFor checks r like Validate and that the team assignment fits a table of N players.
+func (r Rules) ValidateFor(N int) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if len(r.Teams) == 0 {
+		return nil
+	}
+	if len(r.Teams) != N {
+		return fmt.Errorf("rules: teams has %d seats, expected %d", len(r.Teams), N)
+	}
+	ids := map[int]bool{}
+	for _, t := range r.Teams {
+		ids[t] = true
+	}
+	if len(ids) < 2 {
+		return fmt.Errorf("rules: teams needs at least 2 teams")
+	}
+	return nil
+}
+
+// HasTeams reports whether the game is played in teams (Rules.Teams has one entry per seat).
+func (g *GameState) HasTeams() bool {
+	return len(g.Rules.Teams) > 0 && len(g.Rules.Teams) == len(g.Scores)
+}
+
+// Team returns the team ID of player. Without teams every player is a team of their own,
+// identified by the seat.
+func (g *GameState) Team(player int) int {
+	if g.HasTeams() {
+		return g.Rules.Teams[player]
+	}
+	return player
+}
+
+// Teammates returns the other players on player's team in seat order.
+func (g *GameState) Teammates(player int) []int {
+	var mates []int
+	for i := range g.Scores {
+		if i != player && g.Team(i) == g.Team(player) {
+			mates = append(mates, i)
+		}
+	}
+	return mates
+}
+
+// TeamResults returns the standing of every team, ordered by team ID. Teams are ranked by
+// their summed score (TotalScores), then by their summed coins.
+func TeamResults(g *GameState) []TeamResult {
+	scores := TotalScores(g)
+	byID := map[int]*TeamResult{}
+	var teams []*TeamResult
+	for i := range g.Scores {
+		t := byID[g.Team(i)]
+		if t == nil {
+			t = &TeamResult{Team: g.Team(i)}
+			byID[t.Team] = t
+			teams = append(teams, t)
+		}
+		t.Members = append(t.Members, i)
+		t.Score += scores[i]
+		t.Coins += g.Moneys[i][0] + g.Moneys[i][1] + g.Moneys[i][2]
+	}
+	results := make([]TeamResult, len(teams))
+	for k, t := range teams {
+		t.Rank = 1
+		for _, u := range teams {
+			if u.Score > t.Score || (u.Score == t.Score && u.Coins > t.Coins) {
+				t.Rank++
+			}
+		}
+		results[k] = *t
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Team < results[j].Team })
+	return results
+}
+
+// teamRanks ranks the players of a team game: every member gets 1 plus the number of players
+// on better teams, so ranks stay within 1..N as in a game without teams.
+func teamRanks(g *GameState) []int {
+	results := TeamResults(g)
+	ranks := make([]int, len(g.Scores))
+	for _, t := range results {
+		rank := 1
+		for _, u := range results {
+			if u.Rank < t.Rank {
+				rank += len(u.Members)
+			}
+		}
+		for _, i := range t.Members {
+			ranks[i] = rank
+		}
+	}
+	return ranks
+}
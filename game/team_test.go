@@ -0,0 +1,84 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+// teamGame returns a five-player game with teams {0, 2}, {1, 4} and {3}.
+func teamGame(scores []int, moneys [][3]int) *GameState {
+	g := NewGameState(5)
+	g.Rules.Teams = []int{7, 8, 7, 9, 8}
+	copy(g.Scores, scores)
+	copy(g.Moneys, moneys)
+	return g
+}
+
+func TestTeamResults(t *testing.T) {
+	// チーム 7 と 9 は得点 10・コイン 4 で並んで同順位、チーム 8 は 3 位
+	g := teamGame([]int{4, 3, 6, 10, 3}, [][3]int{{1, 0, 0}, {5, 5, 5}, {1, 1, 1}, {2, 1, 1}, {0, 0, 0}})
+	want := []TeamResult{
+		{Team: 7, Members: []int{0, 2}, Score: 10, Coins: 4, Rank: 1},
+		{Team: 8, Members: []int{1, 4}, Score: 6, Coins: 15, Rank: 3},
+		{Team: 9, Members: []int{3}, Score: 10, Coins: 4, Rank: 1},
+	}
+	if got := TeamResults(g); !reflect.DeepEqual(got, want) {
+		t.Errorf("TeamResults = %+v, want %+v", got, want)
+	}
+
+	// 得点が並べばコインの多いチームが上
+	g.Moneys[3] = [3]int{1, 1, 1}
+	results := TeamResults(g)
+	if results[0].Rank != 1 || results[2].Rank != 2 {
+		t.Errorf("coin tiebreak: ranks %d and %d, want 1 and 2", results[0].Rank, results[2].Rank)
+	}
+}
+
+func TestTeamRanks(t *testing.T) {
+	g := teamGame([]int{4, 3, 6, 10, 3}, [][3]int{{5, 0, 0}, {5, 5, 5}, {1, 1, 1}, {1, 1, 1}, {0, 0, 0}})
+	// 2 人のチーム 7 が 1 位、1 人のチーム 9 はその 2 人の次で 3 位、チーム 8 は 4 位
+	if got, want := Ranks(g), []int{1, 4, 1, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ranks = %v, want %v", got, want)
+	}
+
+	g.Moneys[3] = [3]int{6, 1, 1}
+	// 同順位のチームの次は、両チームの人数分だけ下がる
+	if got, want := Ranks(g), []int{1, 4, 1, 1, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ranks with tied teams = %v, want %v", got, want)
+	}
+}
+
+func TestTeamBonusCounts(t *testing.T) {
+	g := teamGame([]int{4, 3, 6, 10, 3}, [][3]int{{1, 0, 0}, {0, 0, 0}, {0, 0, 0}, {0, 0, 0}, {0, 0, 0}})
+	g.Rules.Bonus = "sets"
+	g.Owned[4] = []Jewel{{Category: "a"}, {Category: "a"}, {Category: "a"}}
+	results := TeamResults(g)
+	if results[1].Score != 11 {
+		t.Errorf("team 8 score = %d, want 11 with the set bonus", results[1].Score)
+	}
+}
+
+func TestValidateForTeams(t *testing.T) {
+	tests := []struct {
+		name  string
+		teams []int
+		N     int
+		ok    bool
+	}{
+		{"no teams", nil, 3, true},
+		{"two against two", []int{0, 1, 0, 1}, 4, true},
+		{"uneven teams", []int{0, 1, 0, 2, 1}, 5, true},
+		{"too few seats", []int{0, 1, 0}, 4, false},
+		{"too many seats", []int{0, 1, 0, 1}, 3, false},
+		{"single team", []int{0, 0, 0}, 3, false},
+	}
+	for _, tt := range tests {
+		err := Rules{Teams: tt.teams}.ValidateFor(tt.N)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: ValidateFor(%d) = %v, want ok %v", tt.name, tt.N, err, tt.ok)
+		}
+	}
+	if err := (Rules{Teams: []int{0, 1}, Auction: "bogus"}).ValidateFor(2); err == nil {
+		t.Errorf("ValidateFor accepted rules that fail Validate")
+	}
+}
@@ -15,13 +15,24 @@ func init() {
 // - Point: 1～10 の等確率
 // - Income: 3 種類のうち 1 種を選び、そのコイン収入を 0～5 でランダムに設定。他の 2 種は 0。
 func GenerateJewel() *game.Jewel {
+	return generateJewel(rand.Intn)
+}
+
+// NewDeck は seed から決定的に n 個の Jewel 列を生成します。
+// 同じ seed からは常に同じ列が得られるため、複数の対局で同じ宝石順を再現できます。
+func NewDeck(seed int64, n int) []*game.Jewel {
+	return NewDeckFrom(Uniform{}, seed, n)
+}
+
+// generateJewel は intn を乱数源として GenerateJewel と同じ規則で Jewel を生成します。
+func generateJewel(intn func(int) int) *game.Jewel {
 	// 得点を 1～10 の範囲で生成
-	point := rand.Intn(10) + 1
+	point := intn(10) + 1
 
 	// 収入配列を初期化し、ランダムに 1 種類を設定
 	income := [3]int{0, 0, 0}
-	coinType := rand.Intn(3)        // 0:赤, 1:緑, 2:青
-	income[coinType] = rand.Intn(6) // 0～5
+	coinType := intn(3)        // 0:赤, 1:緑, 2:青
+	income[coinType] = intn(6) // 0～5
 
 	return &game.Jewel{
 		Point:  point,
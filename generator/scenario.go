@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+
+	"github.com/montplusa/auction-game/game"
+)
+
+// Scenario は決まった状況からゲームを再生するための設定です。JSON ファイルに書きます。
+//
+// 開始局面は State があればそれ、なければ初期局面 (Moneys があれば所持コインを置き換え) です。
+// State はオークションの開始時点で、そのフェーズの収入は支払い済みとみなします。
+// 宝石は Jewels の順に出て、尽きたら対局のルールの JewelSource で補います。
+type Scenario struct {
+	Name    string          `json:"name,omitempty"`
+	Players int             `json:"players,omitempty"` // 人数 (State や Moneys があれば省略可)
+	Jewels  []game.Jewel    `json:"jewels"`            // 開始局面から出る宝石の列
+	Moneys  [][3]int        `json:"moneys,omitempty"`  // 初期局面の所持コイン
+	State   *game.GameState `json:"state,omitempty"`   // 途中局面
+}
+
+// LoadScenario はシナリオを JSON ファイルから読み込みます。
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := ParseScenario(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return s, nil
+}
+
+// ParseScenario は JSON からシナリオを読み込み、内容を検証します。
+func ParseScenario(data []byte) (*Scenario, error) {
+	s := &Scenario{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(s); err != nil {
+		return nil, fmt.Errorf("scenario: %v", err)
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Scenario) validate() error {
+	N := s.NumPlayers()
+	if N < 2 {
+		return fmt.Errorf("scenario: need at least 2 players")
+	}
+	if s.Players != 0 && s.Players != N || s.Moneys != nil && len(s.Moneys) != N {
+		return fmt.Errorf("scenario: players, moneys and state disagree on the number of players")
+	}
+	if s.State != nil && s.Moneys != nil {
+		return fmt.Errorf("scenario: give either moneys or state, not both")
+	}
+	if st := s.State; st != nil {
+		// 人数は scores の長さで決まるので、ほかの列をそれに揃える
+		if len(st.Incomes) != N || len(st.Moneys) != N || st.Owned != nil && len(st.Owned) != N {
+			return fmt.Errorf("scenario: state has %d scores but incomes, moneys or owned for a different number of players", N)
+		}
+		if st.Phase < 1 || st.Phase > game.NumPhases || st.Round < 1 || st.Round > 3*N {
+			return fmt.Errorf("scenario: state phase %d round %d out of range", st.Phase, st.Round)
+		}
+	}
+	for i, m := range s.Start().Moneys {
+		if m[0] < 0 || m[1] < 0 || m[2] < 0 {
+			return fmt.Errorf("scenario: player %d has negative coins", i)
+		}
+	}
+	if left := s.Start().RemainingAuctions(); len(s.Jewels) > left {
+		return fmt.Errorf("scenario: %d jewels listed but only %d auctions remain", len(s.Jewels), left)
+	}
+	return nil
+}
+
+// NumPlayers はシナリオの人数を返します。
+func (s *Scenario) NumPlayers() int {
+	switch {
+	case s.State != nil:
+		return len(s.State.Scores)
+	case s.Moneys != nil:
+		return len(s.Moneys)
+	}
+	return s.Players
+}
+
+// Start は開始局面の新しいコピーを返します。
+func (s *Scenario) Start() *game.GameState {
+	if s.State != nil {
+		return s.State.Copy()
+	}
+	gs := game.NewGameState(s.NumPlayers())
+	if s.Moneys != nil {
+		copy(gs.Moneys, s.Moneys)
+	}
+	gs.ApplyPhaseIncome()
+	return gs
+}
+
+// NewDeck は開始局面から最後までの宝石列を返します。Jewels の後は src の規則で seed から決定的に生成します。
+func (s *Scenario) NewDeck(src JewelSource, seed int64) []*game.Jewel {
+	r := rand.New(rand.NewSource(seed))
+	deck := make([]*game.Jewel, s.Start().RemainingAuctions())
+	for i := range deck {
+		if i < len(s.Jewels) {
+			j := s.Jewels[i]
+			deck[i] = &j
+		} else {
+			deck[i] = src.Generate(r.Intn)
+		}
+	}
+	return deck
+}
@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadScenario(t *testing.T) {
+	s, err := LoadScenario("../scenarios/phase9_red_vs_blue.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.NumPlayers() != 2 {
+		t.Errorf("NumPlayers = %d, want 2", s.NumPlayers())
+	}
+	gs := s.Start()
+	if gs.Phase != 9 || gs.Round != 1 || gs.Moneys[1] != [3]int{0, 0, 12} {
+		t.Errorf("Start = phase %d round %d moneys %v, want the state of the file", gs.Phase, gs.Round, gs.Moneys)
+	}
+	// フェーズ 9 と 10 の 6 回ずつ。先頭 2 個はファイルの宝石
+	deck := s.NewDeck(Uniform{}, 1)
+	if len(deck) != 12 || deck[0].Point != 8 || deck[1].Income != [3]int{0, 3, 0} {
+		t.Errorf("NewDeck = %d jewels starting %v %v, want 12 starting with the listed jewels", len(deck), deck[0], deck[1])
+	}
+	gs.Moneys[0][0] = 99
+	if s.Start().Moneys[0][0] != 3 {
+		t.Errorf("Start does not return a copy")
+	}
+}
+
+// phase9 is the state of scenarios/phase9_red_vs_blue.json.
+const phase9 = `"state": {"phase": 9, "round": 1, "scores": [40, 42], "incomes": [[2, 0, 0], [0, 0, 3]], "moneys": [[3, 0, 0], [0, 0, 12]]}`
+
+func TestParseScenario(t *testing.T) {
+	tests := []struct {
+		name, json string
+		players    int
+		err        string // 空ならエラーなし
+	}{
+		{"state", `{"jewels": [], ` + phase9 + `}`, 2, ""},
+		{"state with matching players", `{"players": 2, "jewels": [], ` + phase9 + `}`, 2, ""},
+		{"players only", `{"players": 3, "jewels": []}`, 3, ""},
+		{"moneys", `{"jewels": [], "moneys": [[1, 1, 1], [2, 2, 2]]}`, 2, ""},
+		{"too few players", `{"players": 1, "jewels": []}`, 0, "at least 2 players"},
+		{"no players", `{"jewels": []}`, 0, "at least 2 players"},
+		{"players disagree with state", `{"players": 3, "jewels": [], ` + phase9 + `}`, 0, "disagree"},
+		{"players disagree with moneys", `{"players": 3, "jewels": [], "moneys": [[1, 1, 1], [2, 2, 2]]}`, 0, "disagree"},
+		{"moneys and state", `{"jewels": [], "moneys": [[1, 1, 1], [2, 2, 2]], ` + phase9 + `}`, 0, "either moneys or state"},
+		{"short incomes", `{"jewels": [], "state": {"phase": 9, "round": 1, "scores": [40, 42], "incomes": [[2, 0, 0]], "moneys": [[3, 0, 0], [0, 0, 12]]}}`, 0, "2 scores"},
+		{"short owned", `{"jewels": [], "state": {"phase": 9, "round": 1, "scores": [40, 42], "incomes": [[2, 0, 0], [0, 0, 3]], "moneys": [[3, 0, 0], [0, 0, 12]], "owned": [[]]}}`, 0, "2 scores"},
+		{"phase out of range", `{"jewels": [], "state": {"phase": 11, "round": 1, "scores": [40, 42], "incomes": [[2, 0, 0], [0, 0, 3]], "moneys": [[3, 0, 0], [0, 0, 12]]}}`, 0, "out of range"},
+		{"round out of range", `{"jewels": [], "state": {"phase": 9, "round": 7, "scores": [40, 42], "incomes": [[2, 0, 0], [0, 0, 3]], "moneys": [[3, 0, 0], [0, 0, 12]]}}`, 0, "out of range"},
+		{"negative coins", `{"jewels": [], "moneys": [[1, -1, 1], [2, 2, 2]]}`, 0, "negative coins"},
+		{"unknown field", `{"jewels": [], "player": 2}`, 0, "unknown field"},
+	}
+	for _, tt := range tests {
+		s, err := ParseScenario([]byte(tt.json))
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: error %v, want one containing %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		} else if s.NumPlayers() != tt.players {
+			t.Errorf("%s: NumPlayers = %d, want %d", tt.name, s.NumPlayers(), tt.players)
+		}
+	}
+}
+
+func TestParseScenarioJewelCount(t *testing.T) {
+	jewels := func(n int) string {
+		js := make([]string, n)
+		for i := range js {
+			js[i] = `{"point": 1}`
+		}
+		return `"jewels": [` + strings.Join(js, ",") + `]`
+	}
+	// フェーズ 9 ラウンド 1 の 2 人対局には 12 回のオークションが残っている
+	if _, err := ParseScenario([]byte(`{` + jewels(12) + `, ` + phase9 + `}`)); err != nil {
+		t.Errorf("12 jewels for 12 auctions: %v", err)
+	}
+	if _, err := ParseScenario([]byte(`{` + jewels(13) + `, ` + phase9 + `}`)); err == nil || !strings.Contains(err.Error(), "only 12 auctions remain") {
+		t.Errorf("13 jewels for 12 auctions: error %v", err)
+	}
+	// 初期局面の 2 人対局は 10 フェーズ × 6 回
+	if _, err := ParseScenario([]byte(`{"players": 2, ` + jewels(61) + `}`)); err == nil || !strings.Contains(err.Error(), "only 60 auctions remain") {
+		t.Errorf("61 jewels for a full game: error %v", err)
+	}
+}
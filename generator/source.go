@@ -0,0 +1,230 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/montplusa/auction-game/game"
+)
+
+// JewelSource は宝石の生成規則です。
+// intn は乱数源で、intn(n) は [0,n) の整数を返します (rand.Intn や (*rand.Rand).Intn)。
+type JewelSource interface {
+	// Name は Register で登録される名前を返します。
+	Name() string
+	// Generate は intn を乱数源として Jewel を 1 つ生成します。
+	Generate(intn func(int) int) *game.Jewel
+}
+
+// Uniform は元の規則です: 得点は 1～10 の等確率、収入は 3 色のうち 1 色だけが 0～5。
+type Uniform struct{}
+
+func (Uniform) Name() string { return "uniform" }
+
+func (Uniform) Generate(intn func(int) int) *game.Jewel { return generateJewel(intn) }
+
+// Weighted は得点と収入額を重み付きで引きます。収入は Uniform と同じく 1 色だけです。
+type Weighted struct {
+	ID            string // 登録名
+	PointWeights  []int  // PointWeights[i] は得点 i+1 の重み
+	IncomeWeights []int  // IncomeWeights[i] は収入 i の重み
+}
+
+func (w Weighted) Name() string { return w.ID }
+
+func (w Weighted) Generate(intn func(int) int) *game.Jewel {
+	income := [3]int{}
+	income[intn(3)] = pick(intn, w.IncomeWeights)
+	return &game.Jewel{Point: pick(intn, w.PointWeights) + 1, Income: income}
+}
+
+// pick は weights[i] / 重みの合計 の確率で i を返します。
+func pick(intn func(int) int, weights []int) int {
+	sum := 0
+	for _, w := range weights {
+		sum += w
+	}
+	x := intn(sum)
+	for i, w := range weights {
+		if x < w {
+			return i
+		}
+		x -= w
+	}
+	return len(weights) - 1
+}
+
+// MultiColor は複数色の収入を持つ宝石を生成します。
+// 得点は 1～10 の等確率、Colors 色を選んでそれぞれ 0～MaxIncome の収入を与えます。
+type MultiColor struct {
+	ID        string
+	Colors    int // 収入を持つ色の数 (1～3)
+	MaxIncome int // 1 色あたりの収入の上限
+}
+
+func (m MultiColor) Name() string { return m.ID }
+
+func (m MultiColor) Generate(intn func(int) int) *game.Jewel {
+	income := [3]int{}
+	colors := []int{0, 1, 2}
+	for k := 0; k < m.Colors; k++ {
+		// 残りの色から 1 つ選ぶ
+		i := k + intn(3-k)
+		colors[k], colors[i] = colors[i], colors[k]
+		income[colors[k]] = intn(m.MaxIncome + 1)
+	}
+	return &game.Jewel{Point: intn(10) + 1, Income: income}
+}
+
+// Cursed は Base の宝石に、確率 Percent% で負の得点 (-1～-MaxCurse) の「呪われた」宝石を混ぜます。
+// 呪われた宝石も Base と同じ規則の収入を持つので、収入のために得点を犠牲にするかが問われます。
+type Cursed struct {
+	ID       string
+	Base     JewelSource
+	Percent  int // 呪われた宝石の割合 (%)
+	MaxCurse int // 負の得点の絶対値の上限
+}
+
+func (c Cursed) Name() string { return c.ID }
+
+func (c Cursed) Generate(intn func(int) int) *game.Jewel {
+	j := c.Base.Generate(intn)
+	if intn(100) < c.Percent {
+		j.Point = -(intn(c.MaxCurse) + 1)
+	}
+	return j
+}
+
+// Categorized は Base の宝石に Categories から等確率で選んだ種類 (Jewel.Category) を付けます。
+// セット収集ボーナス (game.SetBonus) と組み合わせて使います。
+type Categorized struct {
+	ID         string
+	Base       JewelSource
+	Categories []string
+}
+
+func (c Categorized) Name() string { return c.ID }
+
+func (c Categorized) Generate(intn func(int) int) *game.Jewel {
+	j := c.Base.Generate(intn)
+	j.Category = c.Categories[intn(len(c.Categories))]
+	return j
+}
+
+// check は src の設定で Generate が intn に正でない n を渡さないか (panic しないか) を調べます。
+func check(src JewelSource) error {
+	switch s := src.(type) {
+	case nil:
+		return fmt.Errorf("generator: nil jewel source")
+	case Weighted:
+		if err := checkWeights(s.PointWeights); err != nil {
+			return fmt.Errorf("generator: %s: point weights: %v", s.ID, err)
+		}
+		if err := checkWeights(s.IncomeWeights); err != nil {
+			return fmt.Errorf("generator: %s: income weights: %v", s.ID, err)
+		}
+	case MultiColor:
+		if s.Colors < 1 || s.Colors > 3 || s.MaxIncome < 0 {
+			return fmt.Errorf("generator: %s: need 1-3 colors and a non-negative income, got %d and %d", s.ID, s.Colors, s.MaxIncome)
+		}
+	case Cursed:
+		if s.MaxCurse < 1 {
+			return fmt.Errorf("generator: %s: MaxCurse must be positive, got %d", s.ID, s.MaxCurse)
+		}
+		return check(s.Base)
+	case Categorized:
+		if len(s.Categories) == 0 {
+			return fmt.Errorf("generator: %s: no categories", s.ID)
+		}
+		return check(s.Base)
+	}
+	return nil
+}
+
+// checkWeights は pick に渡せる重み (負でなく、合計が正) かを調べます。
+func checkWeights(weights []int) error {
+	sum := 0
+	for _, w := range weights {
+		if w < 0 {
+			return fmt.Errorf("negative weight %d", w)
+		}
+		sum += w
+	}
+	if sum == 0 {
+		return fmt.Errorf("weights sum to 0")
+	}
+	return nil
+}
+
+var sources = map[string]JewelSource{}
+
+// Register は src を src.Name() で登録します。名前が空または登録済みのとき、
+// および設定が不正で Generate が panic するとき (重みの合計が 0 の Weighted など) は panic します。
+func Register(src JewelSource) {
+	if err := check(src); err != nil {
+		panic(err)
+	}
+	name := src.Name()
+	if name == "" {
+		panic("generator: jewel source with empty name")
+	}
+	if _, dup := sources[name]; dup {
+		panic("generator: duplicate jewel source " + name)
+	}
+	sources[name] = src
+}
+
+// Lookup は名前で登録された JewelSource を返します。空の名前は "uniform" とみなします。
+func Lookup(name string) (JewelSource, error) {
+	if name == "" {
+		name = Uniform{}.Name()
+	}
+	src, ok := sources[name]
+	if !ok {
+		return nil, fmt.Errorf("generator: unknown jewel source %q (available: %v)", name, Names())
+	}
+	return src, nil
+}
+
+// ForRules は rules.Jewels で指定された JewelSource を返します。
+func ForRules(rules game.Rules) (JewelSource, error) {
+	return Lookup(rules.Jewels)
+}
+
+// Names は登録された JewelSource の名前を昇順で返します。
+func Names() []string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewDeckFrom は NewDeck と同じく seed から決定的に、src の規則で n 個の Jewel 列を生成します。
+func NewDeckFrom(src JewelSource, seed int64, n int) []*game.Jewel {
+	r := rand.New(rand.NewSource(seed))
+	deck := make([]*game.Jewel, n)
+	for i := range deck {
+		deck[i] = src.Generate(r.Intn)
+	}
+	return deck
+}
+
+// Sampler は src の規則で Jewel を生成する関数を返します (game.PlayGame などの nextJewel 用)。
+func Sampler(src JewelSource) func() *game.Jewel {
+	return func() *game.Jewel { return src.Generate(rand.Intn) }
+}
+
+func init() {
+	Register(Uniform{})
+	// 低い得点・少ない収入が出やすい
+	Register(Weighted{ID: "weighted", PointWeights: []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, IncomeWeights: []int{6, 5, 4, 3, 2, 1}})
+	// 2 色に 0～3 の収入
+	Register(MultiColor{ID: "multicolor", Colors: 2, MaxIncome: 3})
+	// 5 個に 1 個が -1～-5 点
+	Register(Cursed{ID: "cursed", Base: Uniform{}, Percent: 20, MaxCurse: 5})
+	// 4 種類のどれか
+	Register(Categorized{ID: "categorized", Base: Uniform{}, Categories: []string{"ruby", "emerald", "sapphire", "diamond"}})
+}
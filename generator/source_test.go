@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/montplusa/auction-game/game"
+)
+
+func TestNewDeckReproducesUniform(t *testing.T) {
+	// JewelSource を入れる前の NewDeck と同じ乱数の引き方
+	r := rand.New(rand.NewSource(42))
+	want := make([]*game.Jewel, 30)
+	for i := range want {
+		point := r.Intn(10) + 1
+		income := [3]int{}
+		income[r.Intn(3)] = r.Intn(6)
+		want[i] = &game.Jewel{Point: point, Income: income}
+	}
+	if got := NewDeck(42, 30); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewDeck(42) differs from the original sequence")
+	}
+	if got := NewDeckFrom(Uniform{}, 42, 30); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewDeckFrom(Uniform) differs from NewDeck")
+	}
+}
+
+func TestRegisteredSources(t *testing.T) {
+	names := Names()
+	want := []string{"categorized", "cursed", "multicolor", "uniform", "weighted"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Names = %v, want %v", names, want)
+	}
+	for _, name := range names {
+		src, err := Lookup(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if src.Name() != name {
+			t.Errorf("source registered as %q is named %q", name, src.Name())
+		}
+		for _, j := range NewDeckFrom(src, 1, 100) {
+			if j.Point == 0 || j.Point > 10 || j.Point < -10 {
+				t.Errorf("%s generated point %d", name, j.Point)
+			}
+		}
+	}
+	if src, err := Lookup(""); err != nil || src.Name() != "uniform" {
+		t.Errorf("Lookup(\"\") = %v, %v, want uniform", src, err)
+	}
+	if _, err := Lookup("bogus"); err == nil {
+		t.Errorf("Lookup accepted an unknown source")
+	}
+}
+
+func TestRegisterRejectsInvalidSources(t *testing.T) {
+	tests := []struct {
+		name string
+		src  JewelSource
+	}{
+		{"zero weights", Weighted{ID: "w", PointWeights: []int{0, 0}, IncomeWeights: []int{1}}},
+		{"no income weights", Weighted{ID: "w", PointWeights: []int{1}}},
+		{"negative weight", Weighted{ID: "w", PointWeights: []int{2, -1}, IncomeWeights: []int{1}}},
+		{"no colors", MultiColor{ID: "m", MaxIncome: 3}},
+		{"no curse", Cursed{ID: "c", Base: Uniform{}, Percent: 20}},
+		{"cursed without base", Cursed{ID: "c", Percent: 20, MaxCurse: 5}},
+		{"bad base", Categorized{ID: "k", Base: Cursed{ID: "c", Base: Uniform{}}, Categories: []string{"a"}}},
+		{"no categories", Categorized{ID: "k", Base: Uniform{}}},
+		{"empty name", Weighted{PointWeights: []int{1}, IncomeWeights: []int{1}}},
+		{"duplicate", Uniform{}},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: Register did not panic", tt.name)
+				}
+			}()
+			Register(tt.src)
+		}()
+	}
+}
@@ -0,0 +1,213 @@
+// Package gym exposes the auction game as a reinforcement-learning environment in the style of
+// OpenAI Gym: one learning seat plays against registered AIs through Reset and Step.
+package gym
+
+import (
+	"fmt"
+
+	"github.com/montplusa/auction-game/ai/nn"
+	"github.com/montplusa/auction-game/game"
+	"github.com/montplusa/auction-game/generator"
+)
+
+// DefaultIncrements is the action set used when Config.Increments is empty.
+var DefaultIncrements = []int{1, 2, 4, 8}
+
+// Config describes an environment.
+type Config struct {
+	Opponents  []string   // 他の席の AI の spec。要素数 + 1 が卓の人数になる
+	Seat       int        // 学習する席。負なら Reset の seed から決める (seed mod 人数)
+	Increments []int      // 行動として使う上乗せ額 (空なら DefaultIncrements)
+	Rules      game.Rules // ゲームのルール設定 (宝石の生成規則など)
+}
+
+// Env is a Gym-style environment. Observations are nn.EncodeState vectors seen from the learning
+// seat, so policies trained here can be plugged into the NeuralNet AI's feature space.
+//
+// Action 0 passes; action 1+c*len(Increments)+i bids AuctionState.MaxValue with color c raised by
+// Increments[i]. An illegal action is treated as a pass, exactly as the game treats invalid bids.
+// In sealed-bid auctions the learning seat acts once per auction and MaxValue is zero, so the
+// actions are hidden bids of Increments[i] coins of color c.
+// The reward is 0 until the game ends and then 1 for first place down to -1 for last place
+// (shared ranks share the reward). In a team game the places are those of the teams.
+type Env struct {
+	cfg    Config
+	source generator.JewelSource
+	ctors  []game.AICtor
+	N      int
+
+	seat    int
+	learner *learner
+	sim     *game.Simulator
+}
+
+// learner is the policy of the learning seat: it plays the bid decoded from the last action.
+type learner struct{ bid [3]int }
+
+func (l *learner) GetName() string { return "Learner" }
+
+func (l *learner) SelectAction(*game.GameState, *game.AuctionState, *game.Jewel) [3]int {
+	return l.bid
+}
+
+// New validates cfg and returns an environment. Call Reset before Step. Dutch auctions are
+// rejected: the action set has no accept/decline decision.
+func New(cfg Config) (*Env, error) {
+	if len(cfg.Opponents) < 1 {
+		return nil, fmt.Errorf("gym: need at least 1 opponent")
+	}
+	if len(cfg.Increments) == 0 {
+		cfg.Increments = DefaultIncrements
+	}
+	for _, k := range cfg.Increments {
+		if k < 1 {
+			return nil, fmt.Errorf("gym: increments must be positive, got %d", k)
+		}
+	}
+	N := len(cfg.Opponents) + 1
+	if cfg.Seat >= N {
+		return nil, fmt.Errorf("gym: seat %d out of range for %d players", cfg.Seat, N)
+	}
+	ctors := make([]game.AICtor, len(cfg.Opponents))
+	for i, spec := range cfg.Opponents {
+		info, p, err := game.ParseAISpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("gym: %v", err)
+		}
+		ctors[i] = func() game.AI { return info.Instantiate(p) }
+	}
+	if err := cfg.Rules.ValidateFor(N); err != nil {
+		return nil, fmt.Errorf("gym: %v", err)
+	}
+	if cfg.Rules.Auction == game.AuctionDutch {
+		// ダッチでは誰に尋ねるかが 1 手の中で決まるので、学習席の手番で止められない
+		return nil, fmt.Errorf("gym: dutch auctions are not supported")
+	}
+	source, err := generator.ForRules(cfg.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("gym: %v", err)
+	}
+	return &Env{cfg: cfg, source: source, ctors: ctors, N: N}, nil
+}
+
+// ObservationSize returns the length of observation vectors.
+func (e *Env) ObservationSize() int { return nn.StateSize }
+
+// NumActions returns the number of discrete actions.
+func (e *Env) NumActions() int { return 1 + 3*len(e.cfg.Increments) }
+
+// Seat returns the learning seat of the current episode.
+func (e *Env) Seat() int { return e.seat }
+
+// State returns the current game, auction and jewel on sale. The auction is nil once the game
+// has ended. The returned values must not be modified.
+func (e *Env) State() (*game.GameState, *game.AuctionState, *game.Jewel) {
+	return e.sim.State, e.sim.Auction, e.sim.Jewel
+}
+
+// Reset starts a new game on the jewel sequence generated from seed, with freshly constructed
+// opponents, and plays until the learning seat has to act. It returns the first observation.
+func (e *Env) Reset(seed int64) []float64 {
+	e.seat = e.cfg.Seat
+	if e.seat < 0 {
+		e.seat = int(((seed % int64(e.N)) + int64(e.N)) % int64(e.N))
+	}
+	e.learner = &learner{}
+	policies := make([]game.AI, e.N)
+	for i, j := 0, 0; i < e.N; i++ {
+		if i == e.seat {
+			policies[i] = e.learner
+			continue
+		}
+		policies[i] = e.ctors[j]()
+		j++
+	}
+	deck := generator.NewDeckFrom(e.source, seed, game.JewelsPerGame(e.N))
+	next := game.DeckJewels(deck)
+	gs := game.StartGame(e.N, e.cfg.Rules)
+	e.sim = game.NewSimulator(gs, nil, gs.DrawJewel(next), policies, next)
+	e.advance()
+	return e.observe()
+}
+
+// Step plays action for the learning seat, lets the opponents move until the learning seat has
+// to act again or the game ends, and returns the next observation, the reward and whether the
+// game has ended.
+func (e *Env) Step(action int) (obs []float64, reward float64, done bool) {
+	if e.sim == nil {
+		panic("gym: Step called before Reset")
+	}
+	if e.sim.Done() {
+		panic("gym: Step called after the game ended")
+	}
+	e.learner.bid = e.Decode(action)
+	e.sim.Step()
+	e.advance()
+	if e.sim.Done() {
+		reward = e.reward()
+	}
+	return e.observe(), reward, e.sim.Done()
+}
+
+// Decode returns the bid that action stands for in the current auction.
+func (e *Env) Decode(action int) [3]int {
+	if action <= 0 || action >= e.NumActions() || e.sim.Auction == nil {
+		return [3]int{}
+	}
+	a := action - 1
+	k := len(e.cfg.Increments)
+	bid := e.sim.Auction.MaxValue
+	bid[a/k] += e.cfg.Increments[a%k]
+	return bid
+}
+
+// LegalActions returns, for every action, whether it is a legal bid right now. Pass is always legal.
+func (e *Env) LegalActions() []bool {
+	mask := make([]bool, e.NumActions())
+	mask[0] = true
+	if e.sim.Auction == nil {
+		return mask
+	}
+	for a := 1; a < len(mask); a++ {
+		mask[a] = game.IsLegalBid(e.sim.State, e.sim.Auction, e.seat, e.Decode(a))
+	}
+	return mask
+}
+
+// advance lets the opponents play until the learning seat is to act in the current auction. A
+// sealed-bid auction is played in a single step in which every seat bids, so the learning seat
+// acts at the start of every auction.
+func (e *Env) advance() {
+	sealed := e.cfg.Rules.Auction == game.AuctionSealed
+	for !e.sim.Done() {
+		as := e.sim.Auction
+		if as.Active[e.seat] && (as.Turn == e.seat || sealed) {
+			return
+		}
+		e.sim.Step()
+	}
+}
+
+// observe encodes the position for the learning seat. After the game ends the last jewel is
+// encoded against an empty auction.
+func (e *Env) observe() []float64 {
+	as := e.sim.Auction
+	if as == nil {
+		as = game.NewAuctionState(0, e.N)
+	}
+	return nn.EncodeState(e.sim.State, as, e.sim.Jewel, e.seat)
+}
+
+func (e *Env) reward() float64 {
+	gs := e.sim.State
+	if gs.HasTeams() {
+		teams := game.TeamResults(gs)
+		for _, t := range teams {
+			if t.Team == gs.Team(e.seat) {
+				return 1 - 2*float64(t.Rank-1)/float64(len(teams)-1)
+			}
+		}
+	}
+	rank := game.Ranks(gs)[e.seat]
+	return 1 - 2*float64(rank-1)/float64(e.N-1)
+}
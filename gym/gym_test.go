@@ -0,0 +1,72 @@
+package gym
+
+import (
+	"testing"
+
+	_ "github.com/montplusa/auction-game/ai/all"
+	"github.com/montplusa/auction-game/game"
+)
+
+// play runs one episode from seed, always taking the largest legal action, and returns the number
+// of steps and the final reward.
+func play(t *testing.T, env *Env, seed int64) (steps int, reward float64) {
+	t.Helper()
+	obs := env.Reset(seed)
+	for {
+		if len(obs) != env.ObservationSize() {
+			t.Fatalf("observation has %d values, want %d", len(obs), env.ObservationSize())
+		}
+		action := 0
+		for a, ok := range env.LegalActions() {
+			if ok {
+				action = a
+			}
+		}
+		var done bool
+		obs, reward, done = env.Step(action)
+		steps++
+		if done {
+			return steps, reward
+		}
+		if reward != 0 {
+			t.Fatalf("reward %v before the game ended", reward)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	env, err := New(Config{Opponents: []string{"RandomAI", "MontplusAI Lv3"}, Seat: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, reward := play(t, env, 1); reward < -1 || reward > 1 {
+		t.Errorf("reward %v out of [-1, 1]", reward)
+	}
+}
+
+func TestSealed(t *testing.T) {
+	rules := game.Rules{Auction: game.AuctionSealed}
+	env, err := New(Config{Opponents: []string{"RandomAI", "MontplusAI Lv3"}, Seat: 1, Rules: rules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps, reward := play(t, env, 2)
+	// 封印入札では学習席はすべてのオークションで 1 回ずつ入札する
+	if want := game.JewelsPerGame(3); steps != want {
+		t.Errorf("played %d steps, want one per auction (%d)", steps, want)
+	}
+	if reward < -1 || reward > 1 {
+		t.Errorf("reward %v out of [-1, 1]", reward)
+	}
+	gs, _, _ := env.State()
+	if gs.Scores[1] == 0 {
+		t.Errorf("learning seat never won a jewel with its sealed bids")
+	}
+}
+
+func TestDutchRejected(t *testing.T) {
+	rules := game.Rules{Auction: game.AuctionDutch}
+	if _, err := New(Config{Opponents: []string{"RandomAI"}, Rules: rules}); err == nil {
+		t.Errorf("New accepted dutch auctions")
+	}
+}
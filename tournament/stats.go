@@ -0,0 +1,161 @@
+package tournament
+
+import (
+	"fmt"
+	"math"
+)
+
+// Z95 is the two-sided normal quantile used for 95% confidence intervals.
+const Z95 = 1.959964
+
+// MeanCI returns the sample mean of xs and the half width z*s/sqrt(n) of its normal-approximation
+// confidence interval. The half width is 0 when fewer than two samples are given.
+func MeanCI(xs []float64, z float64) (mean, half float64) {
+	n := float64(len(xs))
+	if n == 0 {
+		return 0, 0
+	}
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= n
+	if n < 2 {
+		return mean, 0
+	}
+	v := 0.0
+	for _, x := range xs {
+		v += (x - mean) * (x - mean)
+	}
+	v /= n - 1
+	return mean, z * math.Sqrt(v/n)
+}
+
+// Paired compares two entrants of the same tournament deal by deal.
+// Because both played the identical jewel sequences, per-deal differences remove most of the luck.
+type Paired struct {
+	A, B        string
+	Deals       int
+	Wins        int // A の平均順位が B より良かった配牌数
+	Losses      int // A の平均順位が B より悪かった配牌数
+	Draws       int
+	RankDiff    float64 // 平均 (B の順位 - A の順位)。正なら A が優勢
+	RankDiffCI  float64
+	ScoreDiff   float64 // 平均 (A の得点 - B の得点)
+	ScoreDiffCI float64
+}
+
+// Significant reports whether the rank difference CI excludes zero.
+func (p Paired) Significant() bool {
+	return math.Abs(p.RankDiff) > p.RankDiffCI && p.Deals > 1
+}
+
+// Compare returns the paired comparison of entrants a and b (indices into Config.AIs).
+func (r *Report) Compare(a, b int) Paired {
+	p := Paired{A: r.Standings[a].Name, B: r.Standings[b].Name, Deals: len(r.Deals)}
+	ranks := make([]float64, len(r.Deals))
+	scores := make([]float64, len(r.Deals))
+	for d, deal := range r.Deals {
+		ranks[d] = deal.Rank[b] - deal.Rank[a]
+		scores[d] = deal.Score[a] - deal.Score[b]
+		switch {
+		case ranks[d] > 0:
+			p.Wins++
+		case ranks[d] < 0:
+			p.Losses++
+		default:
+			p.Draws++
+		}
+	}
+	p.RankDiff, p.RankDiffCI = MeanCI(ranks, Z95)
+	p.ScoreDiff, p.ScoreDiffCI = MeanCI(scores, Z95)
+	return p
+}
+
+// SPRT configures a sequential probability ratio test on decisive deals between two entrants.
+// Each deal where A and B finish with different mean ranks is a Bernoulli trial;
+// H0: P(A better) = 0.5-Delta is tested against H1: P(A better) = 0.5+Delta.
+type SPRT struct {
+	Delta float64 // 勝率の差の検出幅 (例: 0.05)
+	Alpha float64 // 第一種の誤り率
+	Beta  float64 // 第二種の誤り率
+}
+
+// DefaultSPRT detects a 55% versus 45% paired win rate with 5% error rates.
+var DefaultSPRT = SPRT{Delta: 0.05, Alpha: 0.05, Beta: 0.05}
+
+// Verdict is the state of an SPRT.
+type Verdict int
+
+const (
+	Undecided Verdict = iota // まだ結論が出ていない
+	Better                   // A が有意に強い (H1 採択)
+	Worse                    // A が有意に弱い (H0 採択)
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Better:
+		return "better"
+	case Worse:
+		return "worse"
+	}
+	return "undecided"
+}
+
+// SPRTResult is the outcome of RunSPRT.
+type SPRTResult struct {
+	Paired
+	LLR          float64 // 対数尤度比
+	Lower, Upper float64 // 判定境界
+	Verdict      Verdict
+}
+
+// bounds returns the log-likelihood-ratio thresholds of s.
+func (s SPRT) bounds() (lower, upper float64) {
+	return math.Log(s.Beta / (1 - s.Alpha)), math.Log((1 - s.Beta) / s.Alpha)
+}
+
+// llr returns the log-likelihood ratio of H1 versus H0 after wins and losses.
+func (s SPRT) llr(wins, losses int) float64 {
+	p0, p1 := 0.5-s.Delta, 0.5+s.Delta
+	return float64(wins)*math.Log(p1/p0) + float64(losses)*math.Log((1-p1)/(1-p0))
+}
+
+// RunSPRT plays deals of cfg one by one until the SPRT between entrants a and b reaches a verdict
+// or cfg.Games deals have been played, whichever comes first.
+func RunSPRT(cfg Config, a, b int, s SPRT) (*Report, SPRTResult, error) {
+	if s.Delta <= 0 || s.Delta >= 0.5 || s.Alpha <= 0 || s.Beta <= 0 {
+		return nil, SPRTResult{}, fmt.Errorf("tournament: invalid SPRT parameters %+v", s)
+	}
+	t, err := newRunner(cfg)
+	if err != nil {
+		return nil, SPRTResult{}, err
+	}
+	if a < 0 || a >= len(cfg.AIs) || b < 0 || b >= len(cfg.AIs) || a == b {
+		return nil, SPRTResult{}, fmt.Errorf("tournament: invalid SPRT entrants %d and %d", a, b)
+	}
+	res := SPRTResult{}
+	res.Lower, res.Upper = s.bounds()
+	wins, losses := 0, 0
+	for d := 0; d < cfg.Games; d++ {
+		deal := t.playDeal(cfg.Seed + int64(d))
+		switch {
+		case deal.Rank[a] < deal.Rank[b]:
+			wins++
+		case deal.Rank[a] > deal.Rank[b]:
+			losses++
+		}
+		res.LLR = s.llr(wins, losses)
+		if res.LLR >= res.Upper {
+			res.Verdict = Better
+			break
+		}
+		if res.LLR <= res.Lower {
+			res.Verdict = Worse
+			break
+		}
+	}
+	rep := t.report()
+	res.Paired = rep.Compare(a, b)
+	return rep, res, nil
+}
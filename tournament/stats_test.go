@@ -0,0 +1,126 @@
+package tournament
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMeanCI(t *testing.T) {
+	tests := []struct {
+		xs         []float64
+		mean, half float64
+	}{
+		{nil, 0, 0},
+		{[]float64{3}, 3, 0},
+		{[]float64{2, 2, 2}, 2, 0},
+		// 不偏分散 5/3、半幅 sqrt(5/3/4)
+		{[]float64{1, 2, 3, 4}, 2.5, math.Sqrt(5.0 / 12)},
+	}
+	for _, tt := range tests {
+		mean, half := MeanCI(tt.xs, 1)
+		if math.Abs(mean-tt.mean) > 1e-12 || math.Abs(half-tt.half) > 1e-12 {
+			t.Errorf("MeanCI(%v, 1) = %v, %v, want %v, %v", tt.xs, mean, half, tt.mean, tt.half)
+		}
+	}
+	if _, half := MeanCI([]float64{1, 2, 3, 4}, Z95); math.Abs(half-Z95*math.Sqrt(5.0/12)) > 1e-12 {
+		t.Errorf("MeanCI does not scale the half width by z: %v", half)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	rep := &Report{
+		Standings: []Standing{{Name: "A"}, {Name: "B"}, {Name: "C"}},
+		Deals: []Deal{
+			{Rank: []float64{1, 2, 3}, Score: []float64{30, 20, 10}},
+			{Rank: []float64{2, 1, 3}, Score: []float64{20, 25, 10}},
+			{Rank: []float64{1.5, 1.5, 3}, Score: []float64{22, 22, 10}},
+			{Rank: []float64{1, 3, 2}, Score: []float64{40, 10, 15}},
+		},
+	}
+	p := rep.Compare(0, 1)
+	if p.A != "A" || p.B != "B" || p.Deals != 4 || p.Wins != 2 || p.Losses != 1 || p.Draws != 1 {
+		t.Errorf("Compare(0, 1) = %+v, want 2 wins, 1 loss and 1 draw of A over B", p)
+	}
+	// 順位差 (1, -1, 0, 2)、得点差 (10, -5, 0, 30)
+	if p.RankDiff != 0.5 || p.ScoreDiff != 8.75 {
+		t.Errorf("Compare(0, 1) = rank diff %v and score diff %v, want 0.5 and 8.75", p.RankDiff, p.ScoreDiff)
+	}
+	if p.Significant() {
+		t.Errorf("Compare(0, 1) is significant with rank diff %v ±%v", p.RankDiff, p.RankDiffCI)
+	}
+	// A は毎回 C より 1 以上良い
+	if p := rep.Compare(0, 2); p.Wins != 4 || p.RankDiff <= 0 || !p.Significant() {
+		t.Errorf("Compare(0, 2) = %+v, want a significant win of A", p)
+	}
+	if p := rep.Compare(2, 0); p.Losses != 4 || p.RankDiff >= 0 || !p.Significant() {
+		t.Errorf("Compare(2, 0) = %+v, want a significant loss of C", p)
+	}
+}
+
+func TestSPRTBounds(t *testing.T) {
+	lower, upper := DefaultSPRT.bounds()
+	if math.Abs(lower+math.Log(19)) > 1e-12 || math.Abs(upper-math.Log(19)) > 1e-12 {
+		t.Errorf("bounds = %v, %v, want ±log 19", lower, upper)
+	}
+	// 勝ち 1 回と負け 1 回は打ち消し合う
+	if llr := DefaultSPRT.llr(7, 7); math.Abs(llr) > 1e-12 {
+		t.Errorf("llr(7, 7) = %v, want 0", llr)
+	}
+	if llr := DefaultSPRT.llr(1, 0); math.Abs(llr-math.Log(0.55/0.45)) > 1e-12 {
+		t.Errorf("llr(1, 0) = %v, want log(0.55/0.45)", llr)
+	}
+}
+
+func TestRunSPRT(t *testing.T) {
+	cfg := Config{AIs: []string{"Tournament Greedy", "Tournament Passer"}, Games: 100, Seed: 1, Duplicate: true}
+	// log(0.55/0.45) ≈ 0.2007 なので 15 勝で log 19 ≈ 2.944 を超える
+	rep, res, err := RunSPRT(cfg, 0, 1, DefaultSPRT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Verdict != Better || len(rep.Deals) != 15 || res.Wins != 15 || res.LLR < res.Upper {
+		t.Errorf("greedy vs passer: %v after %d deals (LLR %v), want better after 15", res.Verdict, len(rep.Deals), res.LLR)
+	}
+	_, res, err = RunSPRT(cfg, 1, 0, DefaultSPRT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Verdict != Worse || res.Deals != 15 || res.Losses != 15 {
+		t.Errorf("passer vs greedy: %v after %d deals, want worse after 15", res.Verdict, res.Deals)
+	}
+
+	// 上限に達したら結論なしで止まる
+	cfg.Games = 5
+	rep, res, err = RunSPRT(cfg, 0, 1, DefaultSPRT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Verdict != Undecided || len(rep.Deals) != 5 {
+		t.Errorf("capped run: %v after %d deals, want undecided after 5", res.Verdict, len(rep.Deals))
+	}
+	// 引き分けの配牌は LLR を動かさない
+	cfg.AIs = []string{"Tournament Passer", "Tournament Passer"}
+	if _, res, err := RunSPRT(cfg, 0, 1, DefaultSPRT); err != nil || res.Verdict != Undecided || res.LLR != 0 || res.Draws != 5 {
+		t.Errorf("passers: %+v, %v, want five draws and LLR 0", res, err)
+	}
+}
+
+func TestRunSPRTRejectsBadInput(t *testing.T) {
+	cfg := Config{AIs: []string{"Tournament Greedy", "Tournament Passer"}, Games: 10}
+	tests := []struct {
+		name string
+		a, b int
+		s    SPRT
+	}{
+		{"zero delta", 0, 1, SPRT{Alpha: 0.05, Beta: 0.05}},
+		{"delta of one half", 0, 1, SPRT{Delta: 0.5, Alpha: 0.05, Beta: 0.05}},
+		{"zero alpha", 0, 1, SPRT{Delta: 0.05, Beta: 0.05}},
+		{"same entrant", 1, 1, DefaultSPRT},
+		{"entrant out of range", 0, 2, DefaultSPRT},
+	}
+	for _, tt := range tests {
+		if _, _, err := RunSPRT(cfg, tt.a, tt.b, tt.s); err == nil {
+			t.Errorf("%s: RunSPRT succeeded, want an error", tt.name)
+		}
+	}
+}
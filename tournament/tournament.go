@@ -0,0 +1,259 @@
+// Package tournament plays many headless games between registered AIs and aggregates the results.
+package tournament
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/montplusa/auction-game/game"
+	"github.com/montplusa/auction-game/generator"
+)
+
+// Config describes a tournament.
+type Config struct {
+	AIs       []string   // 参加 AI の spec (ID または表示名、必要なら "{alpha=1.5}" のようなパラメータ付き)。要素数がそのまま卓の人数になる
+	Games     int        // 配牌（宝石列の seed）の数
+	Seed      int64      // 最初の配牌の seed。i 番目の配牌は Seed+i を使う
+	Duplicate bool       // true なら各配牌を全ての席順ローテーションで再生する
+	Rules     game.Rules // ゲームのルール設定 (宝石の生成規則など)
+	// Scenario があれば各対局をその開始局面と宝石列から始める (宝石列の残りは配牌の seed で生成)
+	Scenario *generator.Scenario
+}
+
+// Deal is the outcome of one seeded jewel sequence.
+// With Duplicate enabled every value is the mean over all seat rotations played on that deal,
+// so the seat advantage and the luck of the draw cancel out between entrants.
+type Deal struct {
+	Seed      int64
+	Rank      []float64 // 参加者ごとの順位
+	Score     []float64 // 参加者ごとの得点 (セット収集ボーナスを含む)
+	ScoreDiff []float64 // 参加者ごとの (得点 - 卓の平均得点)
+}
+
+// Standing summarizes one entrant over the whole tournament.
+type Standing struct {
+	Name          string
+	Games         int     // 実際に対局した回数（ローテーションを含む）
+	MeanRank      float64 // 配牌ごとの平均順位の平均
+	RankCI        float64 // MeanRank の 95% 信頼区間の半幅
+	MeanScore     float64
+	ScoreCI       float64
+	MeanScoreDiff float64 // 卓平均との差の平均
+	ScoreDiffCI   float64
+	WinRate       float64 // 1 位（同率を含む）になった対局の割合
+}
+
+// TeamStanding summarizes one lineup of teammates in a team game (Rules.Teams). With Duplicate
+// the seat rotations can change who plays together, so every lineup that occurred is listed.
+type TeamStanding struct {
+	Members   []int   // チームを組んだ参加者 (Config.AIs の添字の昇順)
+	Name      string  // "添字:表示名" を " + " でつないだもの
+	Games     int     // このチームで対局した回数
+	MeanScore float64 // チームの合計得点の平均
+	ScoreCI   float64
+	WinRate   float64 // チームが 1 位（同率を含む）になった対局の割合
+}
+
+// Report is the result of Run.
+type Report struct {
+	Config    Config
+	Rotations int // 1 配牌あたりの対局数
+	Deals     []Deal
+	Standings []Standing     // Config.AIs と同じ順
+	Teams     []TeamStanding // チーム戦のときだけ。最初に現れた順
+}
+
+// Run plays the tournament described by cfg.
+// Entrant e sits at seat (e+r)%N in rotation r; without Duplicate only rotation 0 is played.
+func Run(cfg Config) (*Report, error) {
+	t, err := newRunner(cfg)
+	if err != nil {
+		return nil, err
+	}
+	for d := 0; d < cfg.Games; d++ {
+		t.playDeal(cfg.Seed + int64(d))
+	}
+	return t.report(), nil
+}
+
+// runner plays deals one at a time so that callers can stop early.
+type runner struct {
+	cfg       Config
+	source    generator.JewelSource
+	ctors     []game.AICtor
+	names     []string // 参加者の表示名
+	rotations int
+	deals     []Deal
+	wins      []int
+	teams     []*teamLog // チーム戦の組み合わせごとの成績
+}
+
+// teamLog collects the games of one lineup of teammates.
+type teamLog struct {
+	key     string // members を文字列にしたもの
+	members []int
+	scores  []float64
+	wins    int
+}
+
+func newRunner(cfg Config) (*runner, error) {
+	N := len(cfg.AIs)
+	if N < 2 {
+		return nil, fmt.Errorf("tournament: need at least 2 AIs, got %d", N)
+	}
+	if cfg.Games < 1 {
+		return nil, fmt.Errorf("tournament: games must be positive, got %d", cfg.Games)
+	}
+	ctors := make([]game.AICtor, N)
+	names := make([]string, N)
+	for e, name := range cfg.AIs {
+		info, p, err := game.ParseAISpec(name)
+		if err != nil {
+			return nil, fmt.Errorf("tournament: %v", err)
+		}
+		ctors[e] = func() game.AI { return info.Instantiate(p) }
+		names[e] = info.Spec(p)
+	}
+	if err := cfg.Rules.ValidateFor(N); err != nil {
+		return nil, fmt.Errorf("tournament: %v", err)
+	}
+	source, err := generator.ForRules(cfg.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("tournament: %v", err)
+	}
+	if cfg.Scenario != nil && cfg.Scenario.NumPlayers() != N {
+		return nil, fmt.Errorf("tournament: scenario is for %d players, got %d AIs", cfg.Scenario.NumPlayers(), N)
+	}
+	rotations := 1
+	if cfg.Duplicate {
+		rotations = N
+	}
+	return &runner{cfg: cfg, source: source, ctors: ctors, names: names, rotations: rotations, wins: make([]int, N)}, nil
+}
+
+// start returns the position every game of the tournament starts from.
+func (t *runner) start() *game.GameState {
+	if t.cfg.Scenario != nil {
+		gs := t.cfg.Scenario.Start()
+		gs.Rules = t.cfg.Rules
+		return gs
+	}
+	return game.StartGame(len(t.ctors), t.cfg.Rules)
+}
+
+// playDeal plays every rotation of the deal generated from seed and returns its averaged result.
+func (t *runner) playDeal(seed int64) Deal {
+	N := len(t.ctors)
+	var deck []*game.Jewel
+	if t.cfg.Scenario != nil {
+		deck = t.cfg.Scenario.NewDeck(t.source, seed)
+	} else {
+		deck = generator.NewDeckFrom(t.source, seed, game.JewelsPerGame(N))
+	}
+	deal := Deal{
+		Seed:      seed,
+		Rank:      make([]float64, N),
+		Score:     make([]float64, N),
+		ScoreDiff: make([]float64, N),
+	}
+	for r := 0; r < t.rotations; r++ {
+		ais := make([]game.AI, N)
+		for e := range t.ctors {
+			ais[(e+r)%N] = t.ctors[e]()
+		}
+		gs := game.PlayGameFrom(t.start(), ais, game.DeckJewels(deck))
+		if gs.HasTeams() {
+			t.logTeams(gs, r)
+		}
+		ranks := game.Ranks(gs)
+		scores := game.TotalScores(gs)
+		mean := 0.0
+		for _, sc := range scores {
+			mean += float64(sc)
+		}
+		mean /= float64(N)
+		for e := 0; e < N; e++ {
+			seat := (e + r) % N
+			deal.Rank[e] += float64(ranks[seat])
+			deal.Score[e] += float64(scores[seat])
+			deal.ScoreDiff[e] += float64(scores[seat]) - mean
+			if ranks[seat] == 1 {
+				t.wins[e]++
+			}
+		}
+	}
+	for e := 0; e < N; e++ {
+		deal.Rank[e] /= float64(t.rotations)
+		deal.Score[e] /= float64(t.rotations)
+		deal.ScoreDiff[e] /= float64(t.rotations)
+	}
+	t.deals = append(t.deals, deal)
+	return deal
+}
+
+// logTeams records the team results of a game played in rotation r.
+func (t *runner) logTeams(gs *game.GameState, r int) {
+	N := len(t.ctors)
+	for _, res := range game.TeamResults(gs) {
+		members := make([]int, len(res.Members))
+		for k, seat := range res.Members {
+			members[k] = ((seat-r)%N + N) % N
+		}
+		sort.Ints(members)
+		key := fmt.Sprint(members)
+		var log *teamLog
+		for _, l := range t.teams {
+			if l.key == key {
+				log = l
+				break
+			}
+		}
+		if log == nil {
+			log = &teamLog{key: key, members: members}
+			t.teams = append(t.teams, log)
+		}
+		log.scores = append(log.scores, float64(res.Score))
+		if res.Rank == 1 {
+			log.wins++
+		}
+	}
+}
+
+// report aggregates the deals played so far.
+func (t *runner) report() *Report {
+	N := len(t.ctors)
+	rep := &Report{Config: t.cfg, Rotations: t.rotations, Deals: t.deals}
+	games := len(t.deals) * t.rotations
+	rep.Standings = make([]Standing, N)
+	for e, name := range t.names {
+		st := Standing{Name: name, Games: games}
+		ranks := make([]float64, len(t.deals))
+		scores := make([]float64, len(t.deals))
+		diffs := make([]float64, len(t.deals))
+		for d, deal := range t.deals {
+			ranks[d] = deal.Rank[e]
+			scores[d] = deal.Score[e]
+			diffs[d] = deal.ScoreDiff[e]
+		}
+		st.MeanRank, st.RankCI = MeanCI(ranks, Z95)
+		st.MeanScore, st.ScoreCI = MeanCI(scores, Z95)
+		st.MeanScoreDiff, st.ScoreDiffCI = MeanCI(diffs, Z95)
+		if games > 0 {
+			st.WinRate = float64(t.wins[e]) / float64(games)
+		}
+		rep.Standings[e] = st
+	}
+	for _, l := range t.teams {
+		ts := TeamStanding{Members: l.members, Games: len(l.scores)}
+		names := make([]string, len(l.members))
+		for k, e := range l.members {
+			names[k] = fmt.Sprintf("%d:%s", e, t.names[e])
+		}
+		ts.Name = strings.Join(names, " + ")
+		ts.MeanScore, ts.ScoreCI = MeanCI(l.scores, Z95)
+		ts.WinRate = float64(l.wins) / float64(ts.Games)
+		rep.Teams = append(rep.Teams, ts)
+	}
+	return rep
+}
@@ -0,0 +1,125 @@
+package tournament
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/montplusa/auction-game/game"
+)
+
+// greedy raises by one coin whenever it can afford to, so it wins every jewel against passers.
+type greedy struct{}
+
+func (greedy) GetName() string { return "Tournament Greedy" }
+
+func (greedy) SelectAction(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) [3]int {
+	if raises := game.MinimalRaises(gs, as, as.Turn); len(raises) > 0 {
+		return raises[0]
+	}
+	return [3]int{}
+}
+
+// passer never bids.
+type passer struct{}
+
+func (passer) GetName() string { return "Tournament Passer" }
+
+func (passer) SelectAction(*game.GameState, *game.AuctionState, *game.Jewel) [3]int {
+	return [3]int{}
+}
+
+// seats[id] は id の seatRecorder が手番を持った席の集合
+var seats = map[int]map[int]bool{}
+
+// seatRecorder never bids and records the seats it was asked to move from.
+type seatRecorder struct{ id int }
+
+func (r seatRecorder) GetName() string { return "Seat Recorder" }
+
+func (r seatRecorder) SelectAction(gs *game.GameState, as *game.AuctionState, jewel *game.Jewel) [3]int {
+	if seats[r.id] == nil {
+		seats[r.id] = map[int]bool{}
+	}
+	seats[r.id][as.Turn] = true
+	return [3]int{}
+}
+
+func init() {
+	game.Register(game.AIInfo{ID: "tournament-greedy", Name: "Tournament Greedy", New: func() game.AI { return greedy{} }})
+	game.Register(game.AIInfo{ID: "tournament-passer", Name: "Tournament Passer", New: func() game.AI { return passer{} }})
+	game.Register(game.AIInfo{
+		ID:            "seat-recorder",
+		Name:          "Seat Recorder",
+		Params:        []game.ParamSpec{{Name: "id", Kind: game.ParamInt, Min: 0, Max: 9}},
+		NewWithParams: func(p game.Params) game.AI { return seatRecorder{p.Int("id")} },
+	})
+}
+
+func TestDuplicateRotatesSeats(t *testing.T) {
+	ais := []string{"Seat Recorder", "Seat Recorder{id=1}", "Seat Recorder{id=2}"}
+	for _, duplicate := range []bool{false, true} {
+		seats = map[int]map[int]bool{}
+		rep, err := Run(Config{AIs: ais, Games: 2, Seed: 1, Duplicate: duplicate})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rotations := 1
+		if duplicate {
+			rotations = 3
+		}
+		if rep.Rotations != rotations || rep.Standings[0].Games != 2*rotations {
+			t.Errorf("duplicate %v: %d rotations and %d games, want %d and %d", duplicate, rep.Rotations, rep.Standings[0].Games, rotations, 2*rotations)
+		}
+		for e := 0; e < 3; e++ {
+			// ローテーションなしなら参加者 e は席 e だけ、ありなら全ての席に座る
+			want := map[int]bool{e: true}
+			if duplicate {
+				want = map[int]bool{0: true, 1: true, 2: true}
+			}
+			if !reflect.DeepEqual(seats[e], want) {
+				t.Errorf("duplicate %v: entrant %d sat at %v, want %v", duplicate, e, seats[e], want)
+			}
+		}
+	}
+}
+
+func TestDuplicateAveragesRotations(t *testing.T) {
+	rep, err := Run(Config{AIs: []string{"Tournament Passer", "Tournament Greedy", "Tournament Passer"}, Games: 3, Seed: 5, Duplicate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, deal := range rep.Deals {
+		// どの席順でも Greedy が全ての宝石を取る。Passer 同士は初期コインの差で 2 位と 3 位を分け合う
+		if deal.Rank[1] != 1 || math.Abs(deal.Rank[0]+deal.Rank[2]-5) > 1e-9 {
+			t.Errorf("seed %d: ranks %v, want greedy first and the passers sharing 2nd and 3rd", deal.Seed, deal.Rank)
+		}
+		if deal.Score[0] != 0 || deal.Score[1] <= 0 || math.Abs(deal.ScoreDiff[1]-deal.Score[1]*2/3) > 1e-9 {
+			t.Errorf("seed %d: scores %v and diffs %v", deal.Seed, deal.Score, deal.ScoreDiff)
+		}
+	}
+	st := rep.Standings[1]
+	if st.Name != "Tournament Greedy" || st.MeanRank != 1 || st.RankCI != 0 || st.WinRate != 1 || st.Games != 9 {
+		t.Errorf("greedy standing %+v, want mean rank 1 and every game won", st)
+	}
+	if rep.Standings[0].WinRate != 0 {
+		t.Errorf("passer won %v of its games", rep.Standings[0].WinRate)
+	}
+}
+
+func TestRunRejectsBadConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"one AI", Config{AIs: []string{"Tournament Greedy"}, Games: 1}},
+		{"no games", Config{AIs: []string{"Tournament Greedy", "Tournament Passer"}}},
+		{"unknown AI", Config{AIs: []string{"Tournament Greedy", "nobody"}, Games: 1}},
+		{"unknown jewels", Config{AIs: []string{"Tournament Greedy", "Tournament Passer"}, Games: 1, Rules: game.Rules{Jewels: "bogus"}}},
+	}
+	for _, tt := range tests {
+		if _, err := Run(tt.cfg); err == nil {
+			t.Errorf("%s: Run succeeded, want an error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+// Package tune optimizes the parameters of a registered AI by self-play with SPSA
+// (simultaneous perturbation stochastic approximation).
+package tune
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/montplusa/auction-game/game"
+	"github.com/montplusa/auction-game/tournament"
+)
+
+// Config describes a tuning run.
+type Config struct {
+	AI         string   // 調整する AI の spec。指定したパラメータは開始点になる
+	Tune       []string // 調整するパラメータ名 (空なら全て)
+	Opponents  []string // 対戦相手の spec。調整する AI と合わせて卓を構成する
+	Iterations int      // SPSA の反復回数
+	Deals      int      // 1 回の評価で打つ配牌数 (席順ローテーション込み)
+	Final      int      // 最終評価で打つ配牌数
+	Seed       int64
+	Objective  string     // "score" (卓平均に対する得点比、既定) または "rank" (平均順位)
+	A          float64    // 学習率の初期値 (正規化したパラメータ空間での値)
+	C          float64    // 摂動幅の初期値 (正規化したパラメータ空間での値)
+	Rules      game.Rules // 評価に使うゲームのルール設定
+
+	// Progress は各反復の後に呼ばれる (nil 可)
+	Progress func(iter int, spec string, lossPlus, lossMinus float64)
+}
+
+// Evaluation is the held-out performance of one parameter set against the opponent pool.
+type Evaluation struct {
+	Spec          string      `json:"spec"`
+	Params        game.Params `json:"params"`
+	Deals         int         `json:"deals"`
+	MeanRank      float64     `json:"mean_rank"`
+	RankCI        float64     `json:"rank_ci"`
+	MeanScoreDiff float64     `json:"mean_score_diff"`
+	ScoreDiffCI   float64     `json:"score_diff_ci"`
+	WinRate       float64     `json:"win_rate"`
+	Loss          float64     `json:"loss"` // 最小化した目的関数の値
+}
+
+// Result is the outcome of Run.
+type Result struct {
+	AI         string     `json:"ai"`
+	Opponents  []string   `json:"opponents"`
+	Iterations int        `json:"iterations"`
+	Seed       int64      `json:"seed"`
+	Objective  string     `json:"objective"`
+	Best       Evaluation `json:"best"`  // Start と Tuned のうち Loss が小さい方
+	Start      Evaluation `json:"start"` // 開始点の評価
+	Tuned      Evaluation `json:"tuned"` // SPSA の最終点の評価
+}
+
+// Run tunes cfg.AI. Every evaluation is a duplicate tournament between the candidate and the
+// opponents. Both perturbations of one iteration play the same deals so that the gradient estimate
+// is not swamped by the luck of the draw.
+func Run(cfg Config) (*Result, error) {
+	info, start, err := game.ParseAISpec(cfg.AI)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Objective == "" {
+		cfg.Objective = "score"
+	}
+	loss, ok := objectives[cfg.Objective]
+	if !ok {
+		return nil, fmt.Errorf("tune: unknown objective %q", cfg.Objective)
+	}
+	if len(cfg.Opponents) == 0 {
+		return nil, fmt.Errorf("tune: no opponents")
+	}
+	if cfg.Iterations < 1 || cfg.Deals < 1 || cfg.Final < 1 {
+		return nil, fmt.Errorf("tune: iterations, deals and final must be positive")
+	}
+	specs, err := tunedSpecs(info, cfg.Tune)
+	if err != nil {
+		return nil, err
+	}
+
+	// 正規化空間 [0,1]^d で探索する
+	x := make([]float64, len(specs))
+	for i, ps := range specs {
+		x[i] = (start.Float(ps.Name) - ps.Min) / (ps.Max - ps.Min)
+	}
+	r := rand.New(rand.NewSource(cfg.Seed))
+	bigA := float64(cfg.Iterations) / 10
+	seed := cfg.Seed
+	for k := 0; k < cfg.Iterations; k++ {
+		ak := cfg.A / math.Pow(float64(k+1)+bigA, 0.602)
+		ck := cfg.C / math.Pow(float64(k+1), 0.101)
+		delta := make([]float64, len(x))
+		plus := make([]float64, len(x))
+		minus := make([]float64, len(x))
+		for i := range x {
+			delta[i] = float64(2*r.Intn(2) - 1)
+			plus[i] = clamp01(x[i] + ck*delta[i])
+			minus[i] = clamp01(x[i] - ck*delta[i])
+		}
+		fp, err := evaluate(cfg, info, params(start, specs, plus), cfg.Deals, seed)
+		if err != nil {
+			return nil, err
+		}
+		fm, err := evaluate(cfg, info, params(start, specs, minus), cfg.Deals, seed)
+		if err != nil {
+			return nil, err
+		}
+		seed += int64(cfg.Deals)
+		lp, lm := loss(fp), loss(fm)
+		for i := range x {
+			if plus[i] == minus[i] {
+				continue
+			}
+			x[i] = clamp01(x[i] - ak*(lp-lm)/(plus[i]-minus[i]))
+		}
+		if cfg.Progress != nil {
+			cfg.Progress(k+1, info.Spec(params(start, specs, x)), lp, lm)
+		}
+	}
+
+	// 最終評価は学習に使っていない配牌で行う
+	res := &Result{AI: info.Name, Opponents: cfg.Opponents, Iterations: cfg.Iterations, Seed: cfg.Seed, Objective: cfg.Objective}
+	tuned := params(start, specs, x)
+	st, err := evaluate(cfg, info, start, cfg.Final, seed)
+	if err != nil {
+		return nil, err
+	}
+	res.Start = evaluation(info, start, cfg.Final, st, loss(st))
+	st, err = evaluate(cfg, info, tuned, cfg.Final, seed)
+	if err != nil {
+		return nil, err
+	}
+	res.Tuned = evaluation(info, tuned, cfg.Final, st, loss(st))
+	res.Best = res.Start
+	if res.Tuned.Loss < res.Start.Loss {
+		res.Best = res.Tuned
+	}
+	return res, nil
+}
+
+// tunedSpecs returns the numeric ParamSpecs of info selected by names (all when names is empty).
+// ParamAI parameters and parameters whose range is a single value cannot be tuned and keep the
+// value of the start spec.
+func tunedSpecs(info game.AIInfo, names []string) ([]game.ParamSpec, error) {
+	var specs []game.ParamSpec
+	if len(names) == 0 {
+		for _, ps := range info.Params {
+			if ps.Kind != game.ParamAI && ps.Max > ps.Min {
+				specs = append(specs, ps)
+			}
+		}
+		if len(specs) == 0 {
+			return nil, fmt.Errorf("tune: AI %q has no tunable parameters", info.Name)
+		}
+		return specs, nil
+	}
+	for _, name := range names {
+		found := false
+		for _, ps := range info.Params {
+			if ps.Name != name {
+				continue
+			}
+			if ps.Kind == game.ParamAI {
+				return nil, fmt.Errorf("tune: parameter %q of AI %q is an AI spec and cannot be tuned", name, info.Name)
+			}
+			// 正規化で Max-Min で割るので、幅のない範囲は調整できない
+			if ps.Max <= ps.Min {
+				return nil, fmt.Errorf("tune: parameter %q of AI %q has the fixed range [%v, %v] and cannot be tuned", name, info.Name, ps.Min, ps.Max)
+			}
+			specs = append(specs, ps)
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("tune: AI %q has no parameter %q", info.Name, name)
+		}
+	}
+	return specs, nil
+}
+
+// params maps the normalized point x back onto the parameter ranges, rounding integer parameters.
+func params(base game.Params, specs []game.ParamSpec, x []float64) game.Params {
+	p := game.Params{}
+	for k, v := range base {
+		p[k] = v
+	}
+	for i, ps := range specs {
+		v := ps.Min + x[i]*(ps.Max-ps.Min)
+		if ps.Kind == game.ParamInt {
+			v = math.Round(v)
+		}
+		p[ps.Name] = v
+	}
+	return p
+}
+
+// evaluate plays a duplicate tournament of the candidate against cfg.Opponents and returns its standing.
+func evaluate(cfg Config, info game.AIInfo, p game.Params, deals int, seed int64) (tournament.Standing, error) {
+	tc := tournament.Config{
+		AIs:       append([]string{info.Spec(p)}, cfg.Opponents...),
+		Games:     deals,
+		Seed:      seed,
+		Duplicate: true,
+		Rules:     cfg.Rules,
+	}
+	rep, err := tournament.Run(tc)
+	if err != nil {
+		return tournament.Standing{}, err
+	}
+	return rep.Standings[0], nil
+}
+
+// objectives maps Config.Objective to a loss to minimize. Both are dimensionless so that the
+// default step sizes work for either.
+var objectives = map[string]func(st tournament.Standing) float64{
+	// 卓平均得点に対する得点差の比 (符号を反転)
+	"score": func(st tournament.Standing) float64 {
+		mean := st.MeanScore - st.MeanScoreDiff
+		if mean <= 0 {
+			return 0
+		}
+		return -st.MeanScoreDiff / mean
+	},
+	// 平均順位
+	"rank": func(st tournament.Standing) float64 { return st.MeanRank },
+}
+
+func evaluation(info game.AIInfo, p game.Params, deals int, st tournament.Standing, loss float64) Evaluation {
+	return Evaluation{
+		Spec:          info.Spec(p),
+		Params:        p,
+		Deals:         deals,
+		MeanRank:      st.MeanRank,
+		RankCI:        st.RankCI,
+		MeanScoreDiff: st.MeanScoreDiff,
+		ScoreDiffCI:   st.ScoreDiffCI,
+		WinRate:       st.WinRate,
+		Loss:          loss,
+	}
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,80 @@
+package tune
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/montplusa/auction-game/game"
+)
+
+var testInfo = game.AIInfo{
+	Name: "Tune Test",
+	Params: []game.ParamSpec{
+		{Name: "alpha", Kind: game.ParamFloat, Default: 1, Min: 0, Max: 2},
+		{Name: "n", Kind: game.ParamInt, Default: 5, Min: 0, Max: 10},
+		{Name: "fixed", Kind: game.ParamInt, Default: 3, Min: 3, Max: 3},
+		{Name: "policy", Kind: game.ParamAI, DefaultAI: "random"},
+	},
+}
+
+func TestTunedSpecs(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  []string
+		err   string // 空ならエラーなし
+	}{
+		// 既定では数値で幅のあるパラメータだけ
+		{nil, []string{"alpha", "n"}, ""},
+		{[]string{"n"}, []string{"n"}, ""},
+		{[]string{"n", "alpha"}, []string{"n", "alpha"}, ""},
+		{[]string{"policy"}, nil, "AI spec"},
+		{[]string{"fixed"}, nil, "fixed range"},
+		{[]string{"alpha", "beta"}, nil, `no parameter "beta"`},
+	}
+	for _, tt := range tests {
+		specs, err := tunedSpecs(testInfo, tt.names)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("tunedSpecs(%v): error %v, want one containing %q", tt.names, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("tunedSpecs(%v): %v", tt.names, err)
+			continue
+		}
+		var got []string
+		for _, ps := range specs {
+			got = append(got, ps.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tunedSpecs(%v) = %v, want %v", tt.names, got, tt.want)
+		}
+	}
+
+	noParams := game.AIInfo{Name: "Fixed", Params: testInfo.Params[2:]}
+	if _, err := tunedSpecs(noParams, nil); err == nil {
+		t.Errorf("tunedSpecs accepted an AI without tunable parameters")
+	}
+}
+
+func TestParams(t *testing.T) {
+	base := game.Params{"alpha": 1.0, "n": 5.0, "fixed": 3.0, "policy": "random"}
+	specs, err := tunedSpecs(testInfo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := params(base, specs, []float64{0.25, 0.26})
+	// 整数パラメータは 2.6 を丸めて 3、ほかは base のまま
+	want := game.Params{"alpha": 0.5, "n": 3.0, "fixed": 3.0, "policy": "random"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("params = %v, want %v", got, want)
+	}
+	if base["alpha"] != 1.0 {
+		t.Errorf("params modified its base: %v", base)
+	}
+	if got := params(base, specs, []float64{1, 0}); got.Float("alpha") != 2 || got.Int("n") != 0 {
+		t.Errorf("params at the corner = %v, want alpha=2 n=0", got)
+	}
+}
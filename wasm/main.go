@@ -5,7 +5,7 @@ package main
 
 import (
 	"encoding/json"
-	"sort"
+	"fmt"
 	"syscall/js"
 
 	_ "github.com/montplusa/auction-game/ai/all"
@@ -20,6 +20,7 @@ var (
 	types            []string
 	N                int
 	currentJewel     *game.Jewel
+	nextJewel        func() *game.Jewel
 	auctionState     *game.AuctionState
 	states           []map[string]interface{}
 	idx              int
@@ -33,9 +34,34 @@ func initGame(this js.Value, args []js.Value) interface{} {
 	typesJSON := args[1].String()
 	json.Unmarshal([]byte(typesJSON), &types)
 
+	// Optional rules configuration (JSON)
+	var rules game.Rules
+	if len(args) > 2 && args[2].Type() == js.TypeString && args[2].String() != "" {
+		r, err := game.ParseRules([]byte(args[2].String()))
+		if err != nil {
+			return err.Error()
+		}
+		rules = r
+	}
+	if err := rules.ValidateFor(N); err != nil {
+		return err.Error()
+	}
+	src, err := generator.ForRules(rules)
+	if err != nil {
+		return err.Error()
+	}
+	// 人間の入力は競り上げの手番にしか対応していない (封印入札とダッチでは手番を待てない)
+	if rules.Auction != "" && rules.Auction != game.AuctionOpen {
+		for i := 0; i < N; i++ {
+			if types[i] == "Human" {
+				return fmt.Sprintf("Human players can only play open auctions, not %q", rules.Auction)
+			}
+		}
+	}
+	nextJewel = generator.Sampler(src)
+
 	// New game state
-	gs = game.NewGameState(N)
-	gs.ApplyPhaseIncome()
+	gs = game.StartGame(N, rules)
 
 	// Setup AIs/human channel
 	game.HumanBidCh = make(chan [3]int, 1)
@@ -46,13 +72,15 @@ func initGame(this js.Value, args []js.Value) interface{} {
 			ais[i] = &game.HumanAI{Index: i}
 			continue
 		}
-		if ctor, ok := game.Registry[t]; ok {
-			ais[i] = ctor()
+		ai, err := game.NewAI(t)
+		if err != nil {
+			return err.Error()
 		}
+		ais[i] = ai
 	}
 
 	// First auction
-	currentJewel = generator.GenerateJewel()
+	currentJewel = gs.DrawJewel(nextJewel)
 	auctionState = game.NewAuctionState(0, N)
 
 	// Reset snapshots
@@ -119,7 +147,7 @@ func nextStep(this js.Value, args []js.Value) interface{} {
 			gs.Round = 1
 		}
 		// Start next auction, schedule preview
-		currentJewel = generator.GenerateJewel()
+		currentJewel = gs.DrawJewel(nextJewel)
 		auctionState = game.NewAuctionState((gs.Round-1)%N, N)
 		waitingHuman = false
 		previewJewel = true
@@ -148,11 +176,21 @@ func submitBid(this js.Value, args []js.Value) interface{} {
 
 func recordSnapshot(j *game.Jewel, as *game.AuctionState, isPhaseStart bool) {
 	jInfo := map[string]interface{}{
-		"Point":  currentJewel.Point,
-		"Income": []int{currentJewel.Income[0], currentJewel.Income[1], currentJewel.Income[2]},
+		"Point":    currentJewel.Point,
+		"Income":   []int{currentJewel.Income[0], currentJewel.Income[1], currentJewel.Income[2]},
+		"Category": currentJewel.Category,
+	}
+
+	upcoming := make([]interface{}, len(gs.Upcoming))
+	for i, u := range gs.Upcoming {
+		upcoming[i] = map[string]interface{}{
+			"Point":    u.Point,
+			"Income":   []int{u.Income[0], u.Income[1], u.Income[2]},
+			"Category": u.Category,
+		}
 	}
 
-	ranks := calculateRanks()
+	ranks := game.Ranks(gs)
 	players := make([]interface{}, N)
 	for i := 0; i < N; i++ {
 		m := gs.Moneys[i]
@@ -167,10 +205,25 @@ func recordSnapshot(j *game.Jewel, as *game.AuctionState, isPhaseStart bool) {
 			"Name":       ais[i].GetName(),
 			"Rank":       ranks[i],
 			"Score":      gs.Scores[i],
+			"Bonus":      gs.Bonus(i),
 			"Moneys":     []int{m[0], m[1], m[2]},
 			"Income":     []int{inc[0], inc[1], inc[2]},
 			"CurrentBid": bidSlice,
 			"HasPassed":  as != nil && !as.Active[i],
+			"Team":       gs.Team(i),
+		}
+	}
+
+	var teams []interface{}
+	if gs.HasTeams() {
+		for _, t := range game.TeamResults(gs) {
+			teams = append(teams, map[string]interface{}{
+				"Team":    t.Team,
+				"Members": t.Members,
+				"Score":   t.Score,
+				"Coins":   t.Coins,
+				"Rank":    t.Rank,
+			})
 		}
 	}
 
@@ -180,6 +233,7 @@ func recordSnapshot(j *game.Jewel, as *game.AuctionState, isPhaseStart bool) {
 		if as.MaxPlayer >= 0 {
 			aInfo = map[string]interface{}{"MaxPlayer": as.MaxPlayer, "MaxValue": []int{as.MaxValue[0], as.MaxValue[1], as.MaxValue[2]}}
 		}
+		aInfo["Asking"] = []int{as.Asking[0], as.Asking[1], as.Asking[2]}
 		auction = aInfo
 	} else {
 		auction = nil
@@ -194,6 +248,8 @@ func recordSnapshot(j *game.Jewel, as *game.AuctionState, isPhaseStart bool) {
 		"Turn":         turn,
 		"PhaseStart":   isPhaseStart,
 		"Jewel":        jInfo,
+		"Upcoming":     upcoming,
+		"Teams":        teams,
 		"Auction":      auction,
 		"Players":      players,
 		"WaitingHuman": waitingHuman,
@@ -203,31 +259,6 @@ func recordSnapshot(j *game.Jewel, as *game.AuctionState, isPhaseStart bool) {
 	idx = len(states) - 1
 }
 
-func calculateRanks() []int {
-	type pair struct{ idx, score, moneySum int }
-	arr := make([]pair, N)
-	for i := range arr {
-		sum := gs.Moneys[i][0] + gs.Moneys[i][1] + gs.Moneys[i][2]
-		arr[i] = pair{i, gs.Scores[i], sum}
-	}
-	sort.Slice(arr, func(i, j int) bool {
-		if arr[i].score != arr[j].score {
-			return arr[i].score > arr[j].score
-		}
-		return arr[i].moneySum > arr[j].moneySum
-	})
-	ranks := make([]int, N)
-	rank := 1
-	ranks[arr[0].idx] = rank
-	for i := 1; i < len(arr); i++ {
-		if arr[i].score != arr[i-1].score || arr[i].moneySum != arr[i-1].moneySum {
-			rank = i + 1
-		}
-		ranks[arr[i].idx] = rank
-	}
-	return ranks
-}
-
 func getCurrentState(this js.Value, args []js.Value) interface{} {
 	data, _ := json.Marshal(states[idx])
 	return js.Global().Get("JSON").Call("parse", string(data))
@@ -238,6 +269,25 @@ func getAllStates(this js.Value, args []js.Value) interface{} {
 	return js.Global().Get("JSON").Call("parse", string(data))
 }
 
+// getAIInfos returns the metadata of every registered AI in display-name order.
+func getAIInfos(this js.Value, args []js.Value) interface{} {
+	list := game.ListAIs()
+	infos := make([]map[string]interface{}, len(list))
+	for i, info := range list {
+		infos[i] = map[string]interface{}{
+			"ID":          info.ID,
+			"Name":        info.Name,
+			"Author":      info.Author,
+			"Version":     info.Version,
+			"Description": info.Description,
+			"Tags":        info.Tags,
+			"Params":      info.Params,
+		}
+	}
+	data, _ := json.Marshal(infos)
+	return js.Global().Get("JSON").Call("parse", string(data))
+}
+
 func main() {
 	js.Global().Set("initGame", js.FuncOf(initGame))
 	js.Global().Set("nextStep", js.FuncOf(nextStep))
@@ -245,11 +295,21 @@ func main() {
 	js.Global().Set("getCurrentState", js.FuncOf(getCurrentState))
 	js.Global().Set("getAllStates", js.FuncOf(getAllStates))
 	js.Global().Set("getAvailableAIs", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		keys := make([]interface{}, 0, len(game.Registry))
-		for k := range game.Registry {
-			keys = append(keys, k)
+		list := game.ListAIs()
+		keys := make([]interface{}, len(list))
+		for i, info := range list {
+			keys[i] = info.Name
 		}
 		return js.ValueOf(keys)
 	}))
+	js.Global().Set("getAIInfos", js.FuncOf(getAIInfos))
+	js.Global().Set("getJewelSources", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		names := generator.Names()
+		list := make([]interface{}, len(names))
+		for i, name := range names {
+			list[i] = name
+		}
+		return js.ValueOf(list)
+	}))
 	select {}
 }